@@ -0,0 +1,171 @@
+// Package apperror defines the typed errors services and handlers return.
+// Each error has a kind, which decides the HTTP status, and a stable code
+// clients can match on.
+package apperror
+
+import (
+	"e-commerce/money"
+	"errors"
+	"fmt"
+)
+
+// Error kinds. Match them with errors.Is.
+var (
+	ErrValidation          = errors.New("validation failed")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrNotFound            = errors.New("not found")
+	ErrConflict            = errors.New("conflict")
+	ErrInsufficientStock   = errors.New("insufficient stock")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrTooLarge            = errors.New("too large")
+	ErrUnsupportedMedia    = errors.New("unsupported media type")
+	ErrUnprocessable       = errors.New("unprocessable entity")
+	ErrInternal            = errors.New("internal error")
+)
+
+// FieldError explains why one field of the input was rejected. Code names
+// the rule it broke, e.g. "required", and with Params picks and fills the
+// localized message.
+type FieldError struct {
+	Field   string         `json:"field"`
+	Code    string         `json:"code,omitempty"`
+	Message string         `json:"message"`
+	Params  map[string]any `json:"-"`
+}
+
+// Error is a domain error. Message is safe to show to clients; Err is the
+// underlying cause, which is only logged. Responses use the message
+// catalog entry for Code, filled with Params, and fall back to Message.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Params  map[string]any
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// Is matches errors with the same code, so a package-level *Error can be
+// used as a sentinel for errors built with WithField.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+// WithField returns a copy of e that also blames field, with the field's
+// message appended to e's message.
+func (e *Error) WithField(field FieldError) *Error {
+	separator := ": "
+	if len(e.Fields) > 0 {
+		separator = "; "
+	}
+	copied := *e
+	copied.Message = e.Message + separator + field.Message
+	copied.Fields = append(append([]FieldError{}, e.Fields...), field)
+	return &copied
+}
+
+// With returns a copy of e with the message parameter name set to value.
+func (e *Error) With(name string, value any) *Error {
+	copied := *e
+	copied.Params = make(map[string]any, len(e.Params)+1)
+	for k, v := range e.Params {
+		copied.Params[k] = v
+	}
+	copied.Params[name] = value
+	return &copied
+}
+
+func New(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func Validation(code, message string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message, Fields: fields}
+}
+
+func Unauthorized(code, message string) *Error {
+	return New(ErrUnauthorized, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(ErrForbidden, code, message)
+}
+
+func NotFound(code, message string) *Error {
+	return New(ErrNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(ErrConflict, code, message)
+}
+
+// Unprocessable reports a well-formed request that refers to something
+// that doesn't exist, such as an unknown category_id in a product body.
+func Unprocessable(code, message string, fields ...FieldError) *Error {
+	return &Error{Kind: ErrUnprocessable, Code: code, Message: message, Fields: fields}
+}
+
+// InsufficientStock reports that only available units can be sold.
+func InsufficientStock(available int) *Error {
+	message := fmt.Sprintf("insufficient stock, only %d left", available)
+	return New(ErrInsufficientStock, "insufficient_stock", message).With("available", available)
+}
+
+// InsufficientBalance reports a purchase of total rupiah that balance
+// doesn't cover.
+func InsufficientBalance(total, balance int) *Error {
+	message := fmt.Sprintf("insufficient balance, total price: %s, your balance: %s", money.IDR(total), money.IDR(balance))
+	return New(ErrInsufficientBalance, "insufficient_balance", message).
+		With("total", money.IDR(total)).
+		With("balance", money.IDR(balance))
+}
+
+// Internal reports a failure that isn't the client's fault. err is logged
+// but never shown.
+func Internal(err error, code, message string) *Error {
+	return &Error{Kind: ErrInternal, Code: code, Message: message, Err: err}
+}
+
+// InvalidBody reports a request body that couldn't be decoded. Domain
+// errors, such as the field errors of binding validation, are returned
+// unchanged.
+func InvalidBody(err error) error {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+	return &Error{Kind: ErrValidation, Code: "invalid_body", Message: err.Error(), Err: err}
+}
+
+// Invalid returns err unchanged when it is already a domain error and
+// otherwise reports it as a validation error with code invalid_request.
+func Invalid(err error) error {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+	return &Error{Kind: ErrValidation, Code: "invalid_request", Message: err.Error(), Err: err}
+}
+
+// Code returns the code of the first domain error in err's chain, or "" if
+// there is none.
+func Code(err error) string {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+package apperror
+
+import (
+	"context"
+	"e-commerce/i18n"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{Validation("invalid_price", "price must be greater than zero"), http.StatusBadRequest},
+		{Unauthorized("invalid_token", "Invalid Token"), http.StatusUnauthorized},
+		{Forbidden("admin_required", "Admin access required"), http.StatusForbidden},
+		{NotFound("product_not_found", "Product not found"), http.StatusNotFound},
+		{Conflict("sku_exists", "sku already exists"), http.StatusConflict},
+		{Unprocessable("unknown_category", "category_id does not refer to an existing category"), http.StatusUnprocessableEntity},
+		{InsufficientStock(2), http.StatusConflict},
+		{InsufficientBalance(150000, 20000), http.StatusPaymentRequired},
+		{fmt.Errorf("reserving: %w", NotFound("variant_not_found", "variant not found")), http.StatusNotFound},
+		{Internal(Validation("invalid_price", "price must be greater than zero"), "product_create_failed", "Failed to create product"), http.StatusInternalServerError},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.status, Status(tc.err), tc.err.Error())
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	errInvalidFilter := Validation("invalid_filter", "invalid filter")
+	err := errInvalidFilter.WithField(FieldError{Field: "sort", Message: "sort must be one of created_at, total_price"})
+
+	assert.ErrorIs(t, err, errInvalidFilter)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.NotErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, Validation("invalid_sort", "invalid sort"))
+	assert.EqualError(t, err, "invalid filter: sort must be one of created_at, total_price")
+	assert.Equal(t, []FieldError{{Field: "sort", Message: "sort must be one of created_at, total_price"}}, err.Fields)
+	assert.Empty(t, errInvalidFilter.Fields)
+	assert.Equal(t, "invalid_filter", Code(fmt.Errorf("search: %w", err)))
+	assert.Equal(t, "", Code(errors.New("boom")))
+}
+
+func TestInvalid(t *testing.T) {
+	notFound := NotFound("product_not_found", "Product not found")
+	assert.Same(t, error(notFound), Invalid(notFound))
+
+	err := Invalid(errors.New("strconv.Atoi: parsing \"x\": invalid syntax"))
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Equal(t, "invalid_request", Code(err))
+}
+
+func TestNewProblem(t *testing.T) {
+	problem := NewProblem(Validation("invalid_report", "invalid report").WithField(FieldError{Field: "granularity", Message: "granularity must be day, week or month"}), "/reports/revenue", "en")
+	assert.Equal(t, Problem{
+		Type:     "about:blank",
+		Title:    "Bad Request",
+		Status:   http.StatusBadRequest,
+		Detail:   "invalid report: granularity must be day, week or month",
+		Instance: "/reports/revenue",
+		Code:     "invalid_report",
+		Errors:   []FieldError{{Field: "granularity", Message: "granularity must be day, week or month"}},
+	}, problem)
+
+	problem = NewProblem(errors.New(`pq: relation "products" does not exist`), "/products", "en")
+	assert.Equal(t, "internal_error", problem.Code)
+	assert.Empty(t, problem.Detail)
+
+	problem = NewProblem(Internal(errors.New("disk full"), "image_store_failed", "Failed to store image"), "/products/1/images", "en")
+	assert.Equal(t, "image_store_failed", problem.Code)
+	assert.Equal(t, "failed to store image", problem.Detail)
+
+	problem = NewProblem(Validation("unlisted_code", "message without a catalog entry"), "/products", "id")
+	assert.Equal(t, "message without a catalog entry", problem.Detail)
+}
+
+func TestNewProblemLocalizes(t *testing.T) {
+	problem := NewProblem(InsufficientBalance(1500000, 20000), "/transactions", "id")
+	assert.Equal(t, "Pembayaran Diperlukan", problem.Title)
+	assert.Equal(t, "saldo tidak mencukupi, total harga: Rp1.500.000, saldo Anda: Rp20.000", problem.Detail)
+
+	problem = NewProblem(InsufficientBalance(1500000, 20000), "/transactions", "en")
+	assert.Equal(t, "Payment Required", problem.Title)
+	assert.Equal(t, "insufficient balance, total price: Rp1,500,000, your balance: Rp20,000", problem.Detail)
+
+	fieldErr := Validation("invalid_fields", "invalid fields").
+		WithField(FieldError{Field: "title", Code: "required", Message: "title is required"}).
+		WithField(FieldError{Field: "price", Code: "price", Message: "price must be between 1 and 50,000,000", Params: map[string]any{"min": 1, "max": 50000000}})
+	assert.Equal(t, "invalid fields: title is required; price must be between 1 and 50,000,000", fieldErr.Error())
+	problem = NewProblem(fieldErr, "/products", "id")
+	assert.Equal(t, "isian tidak valid: title wajib diisi; price harus antara 1 dan 50.000.000", problem.Detail)
+	assert.Equal(t, "price harus antara 1 dan 50.000.000", problem.Errors[1].Message)
+	assert.Equal(t, "price must be between 1 and 50,000,000", fieldErr.Fields[1].Message)
+}
+
+func TestWith(t *testing.T) {
+	base := NotFound("product_not_found", "product not found")
+	err := base.With("id", 7)
+	assert.Equal(t, map[string]any{"id": 7}, err.Params)
+	assert.Nil(t, base.Params)
+	assert.ErrorIs(t, err, base)
+}
+
+func TestRespond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(i18n.Middleware(i18n.English))
+	router.GET("/products/:productId", func(c *gin.Context) {
+		Respond(c, NotFound("product_not_found", "Product not found"))
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/products/9", nil)
+	req.Header.Set("Accept-Language", "id-ID,id;q=0.9,en;q=0.8")
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+	var problem Problem
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, "product_not_found", problem.Code)
+	assert.Equal(t, "/products/9", problem.Instance)
+	assert.Equal(t, "produk tidak ditemukan", problem.Detail)
+	assert.Equal(t, "id", rec.Header().Get("Content-Language"))
+}
+
+func TestInvalidBodyKeepsDomainErrors(t *testing.T) {
+	fieldErr := Validation("invalid_fields", "title is required", FieldError{Field: "title", Code: "required", Message: "title is required"})
+	assert.Same(t, fieldErr, InvalidBody(fieldErr))
+	assert.Equal(t, "invalid_body", Code(InvalidBody(errors.New("unexpected EOF"))))
+}
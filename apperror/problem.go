@@ -0,0 +1,144 @@
+package apperror
+
+import (
+	"context"
+	"e-commerce/i18n"
+	"e-commerce/logging"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of problem responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details response. Code and Errors are
+// extension members: Code is stable for each kind of failure and Errors
+// lists the rejected fields of a validation error.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// Status maps err to the HTTP status of its kind. Errors that aren't domain
+// errors are internal server errors, and a request that ran out of time is
+// a gateway timeout whatever it was doing.
+func Status(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	var domainErr *Error
+	if !errors.As(err, &domainErr) {
+		return http.StatusInternalServerError
+	}
+	switch domainErr.Kind {
+	case ErrValidation:
+		return http.StatusBadRequest
+	case ErrUnauthorized:
+		return http.StatusUnauthorized
+	case ErrForbidden:
+		return http.StatusForbidden
+	case ErrNotFound:
+		return http.StatusNotFound
+	case ErrConflict, ErrInsufficientStock:
+		return http.StatusConflict
+	case ErrInsufficientBalance:
+		return http.StatusPaymentRequired
+	case ErrTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case ErrUnsupportedMedia:
+		return http.StatusUnsupportedMediaType
+	case ErrUnprocessable:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// NewProblem describes err for the request at instance in locale. The
+// cause of an internal error is never included.
+func NewProblem(err error, instance, locale string) Problem {
+	status := Status(err)
+	title, ok := i18n.Translate(locale, "status."+strconv.Itoa(status), nil)
+	if !ok {
+		title = http.StatusText(status)
+	}
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    title,
+		Status:   status,
+		Instance: instance,
+	}
+
+	var domainErr *Error
+	switch {
+	case status == http.StatusGatewayTimeout:
+		problem.Code = "timeout"
+		problem.Detail, _ = i18n.Translate(locale, "timeout", nil)
+	case errors.As(err, &domainErr):
+		problem.Code = domainErr.Code
+		problem.Errors = localizeFields(locale, domainErr.Fields)
+		problem.Detail = localizeDetail(locale, domainErr, problem.Errors)
+	default:
+		problem.Code = "internal_error"
+	}
+	return problem
+}
+
+// localizeDetail renders the catalog message for the error's code followed
+// by its field messages, the way WithField builds Message. Codes without a
+// catalog entry keep their Message.
+func localizeDetail(locale string, domainErr *Error, fields []FieldError) string {
+	detail, ok := i18n.Translate(locale, domainErr.Code, domainErr.Params)
+	if !ok {
+		return domainErr.Message
+	}
+	if len(fields) == 0 {
+		return detail
+	}
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field.Message
+	}
+	return detail + ": " + strings.Join(messages, "; ")
+}
+
+func localizeFields(locale string, fields []FieldError) []FieldError {
+	if len(fields) == 0 {
+		return nil
+	}
+	localized := make([]FieldError, len(fields))
+	for i, field := range fields {
+		localized[i] = field
+		if field.Code == "" {
+			continue
+		}
+		params := map[string]any{"field": field.Field}
+		for k, v := range field.Params {
+			params[k] = v
+		}
+		if message, ok := i18n.Translate(locale, "field."+field.Code, params); ok {
+			localized[i].Message = message
+		}
+	}
+	return localized
+}
+
+// Respond aborts the request with the problem for err, in the language of
+// the request, and logs server errors.
+func Respond(c *gin.Context, err error) {
+	problem := NewProblem(err, c.Request.URL.Path, i18n.FromContext(c.Request.Context()))
+	if problem.Status >= http.StatusInternalServerError {
+		logging.Request(c).Error("request failed", "code", problem.Code, "error", err)
+	}
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
@@ -1,8 +1,8 @@
 package auth
 
 import (
-	"fmt"
-	"net/http"
+	"e-commerce/apperror"
+	"e-commerce/logging"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -51,21 +51,20 @@ func AuthenticationMiddleware() gin.HandlerFunc {
 
 		token, err := ValidateToken(tokenString)
 		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token"})
-			c.Abort()
+			apperror.Respond(c, apperror.Unauthorized("invalid_token", "Invalid Token"))
 			return
 		}
 
 		claims, ok := token.Claims.(*Claims)
 
 		if !ok {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get claims from token"})
-			c.Abort()
+			apperror.Respond(c, apperror.Internal(nil, "invalid_token_claims", "Failed to get claims from token"))
 			return
 		}
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
 		c.Set("id", claims.ID)
+		logging.SetUser(c, claims.ID, claims.Role)
 		c.Next()
 	}
 }
@@ -75,30 +74,27 @@ func AuthorizationMiddleware() gin.HandlerFunc {
 
 		token, err := ValidateToken(tokenString)
 		if err != nil || !token.Valid {
-			fmt.Println("Invalid Token:", err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token"})
-			c.Abort()
+			logging.Request(c).Warn("invalid token", "error", err)
+			apperror.Respond(c, apperror.Unauthorized("invalid_token", "Invalid Token"))
 			return
 		}
 
 		claims, ok := token.Claims.(*Claims)
 		if !ok {
-			fmt.Println("Failed to get claims from token")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get claims from token"})
-			c.Abort()
+			apperror.Respond(c, apperror.Internal(nil, "invalid_token_claims", "Failed to get claims from token"))
 			return
 		}
 
 		if claims.Role != "admin" {
-			fmt.Println("Unauthorized role:", claims.Role) // Add this line for debugging
-			c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized"})
-			c.Abort()
+			logging.Request(c).Warn("forbidden role", "user_id", claims.ID, "role", claims.Role)
+			apperror.Respond(c, apperror.Forbidden("admin_required", "Admin access required"))
 			return
 		}
 
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
 		c.Set("id", claims.ID)
+		logging.SetUser(c, claims.ID, claims.Role)
 		c.Next()
 	}
 }
@@ -1,19 +1,202 @@
 package config
 
 import (
+	"e-commerce/entity"
+	"e-commerce/metrics"
 	"e-commerce/models"
+	"e-commerce/services"
+	"e-commerce/tracing"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// Models are the tables ConnectDatabase migrates. /readyz reports the server
+// as not ready until all of them exist.
+var Models = []interface{}{&models.User{}, &models.Address{}, &models.Category{}, &models.Product{}, &models.ProductVariant{}, &models.ProductImage{}, &models.TransactionHistory{}, &models.InventoryMovement{}, &models.StockReservation{}, &models.StockEvent{}, &models.RestockSubscription{}, &models.Coupon{}, &models.CouponRedemption{}, &models.PriceHistory{}, &models.ScheduledPriceChange{}, &models.ProductPrice{}, &models.Invoice{}, &models.InvoiceSequence{}, &models.ScheduledJob{}}
+
 func ConnectDatabase() *gorm.DB {
 	dsn := "host=localhost user=postgres dbname=E-Commerce-Golang password=abo port=5432 sslmode=disable"
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		log.Fatal("Error connecting to database", err)
 	}
-	db.AutoMigrate(&models.User{}, &models.Category{}, &models.Product{}, &models.TransactionHistory{})
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		log.Fatal("Error registering database metrics", err)
+	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		log.Fatal("Error registering database tracing", err)
+	}
+	if err := metrics.RegisterDB(db); err != nil {
+		log.Fatal("Error registering database metrics", err)
+	}
+	db.AutoMigrate(Models...)
+	// Sales reports and transaction history filter by purchase date
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_transaction_histories_created_at ON transaction_histories (created_at)").Error; err != nil {
+		log.Fatal("Error creating transaction date index", err)
+	}
+	if err := uniqueSKUs(db, &models.Product{}, "products", "idx_products_sku"); err != nil {
+		log.Fatal("Error making product SKUs unique", err)
+	}
+	if err := uniqueSKUs(db, &models.ProductVariant{}, "product_variants", "idx_product_variants_sku"); err != nil {
+		log.Fatal("Error making variant SKUs unique", err)
+	}
+	if err := backfillInventoryJournal(db); err != nil {
+		log.Fatal("Error backfilling inventory journal", err)
+	}
+	if err := backfillPriceHistory(db); err != nil {
+		log.Fatal("Error backfilling price history", err)
+	}
+	if err := seedSalesReportJob(db); err != nil {
+		log.Fatal("Error creating the sales report job", err)
+	}
 	return db
 }
+
+// uniqueSKUs replaces the plain SKU index of table with one that is unique
+// among rows that aren't deleted, so the SKU of a deleted product or variant
+// can be reused. Rows without a SKU are left out. Existing duplicates are
+// refused rather than renamed, since other systems may already refer to them.
+func uniqueSKUs(db *gorm.DB, model interface{}, table, plainIndex string) error {
+	var duplicates []string
+	if err := db.Model(model).Where("sku <> ''").
+		Group("sku").Having("COUNT(*) > 1").Pluck("sku", &duplicates).Error; err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("%s share the SKUs %s, give them distinct SKUs first", table, strings.Join(duplicates, ", "))
+	}
+	if err := db.Exec("DROP INDEX IF EXISTS " + plainIndex).Error; err != nil {
+		return err
+	}
+	return db.Exec(fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_sku_unique ON %s (sku) WHERE deleted_at IS NULL AND sku <> ''", table, table)).Error
+}
+
+// backfillInventoryJournal records an opening balance for every product and
+// variant whose stock is not yet explained by the inventory journal.
+func backfillInventoryJournal(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var products []models.Product
+		if err := tx.Find(&products).Error; err != nil {
+			return err
+		}
+		for _, product := range products {
+			var balance int
+			if err := tx.Model(&models.InventoryMovement{}).Where("product_id = ? AND variant_id IS NULL", product.ID).
+				Select("COALESCE(SUM(quantity), 0)").Scan(&balance).Error; err != nil {
+				return err
+			}
+			if err := createOpeningBalance(tx, product.ID, nil, product.Stock, balance); err != nil {
+				return err
+			}
+		}
+
+		var variants []models.ProductVariant
+		if err := tx.Find(&variants).Error; err != nil {
+			return err
+		}
+		for _, variant := range variants {
+			var balance int
+			if err := tx.Model(&models.InventoryMovement{}).Where("variant_id = ?", variant.ID).
+				Select("COALESCE(SUM(quantity), 0)").Scan(&balance).Error; err != nil {
+				return err
+			}
+			variantID := variant.ID
+			if err := createOpeningBalance(tx, variant.ProductID, &variantID, variant.Stock, balance); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func createOpeningBalance(tx *gorm.DB, productID uint, variantID *uint, stock, balance int) error {
+	if stock == balance {
+		return nil
+	}
+	return tx.Create(&models.InventoryMovement{
+		ProductID:  productID,
+		VariantID:  variantID,
+		Type:       services.MovementAdjustment,
+		Quantity:   stock - balance,
+		StockAfter: stock,
+		Note:       "Opening balance",
+	}).Error
+}
+
+// backfillPriceHistory records the current price of every product that has
+// no price history yet.
+func backfillPriceHistory(db *gorm.DB) error {
+	var products []models.Product
+	if err := db.Where("NOT EXISTS (SELECT 1 FROM price_histories WHERE price_histories.product_id = products.id)").
+		Find(&products).Error; err != nil {
+		return err
+	}
+	for _, product := range products {
+		if err := db.Create(&models.PriceHistory{
+			ProductID: product.ID,
+			NewPrice:  product.Price,
+			Reason:    entity.PriceReasonInitial,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PriceScheduleInterval is how often due scheduled price changes are
+// applied, configured with PRICE_SCHEDULE_INTERVAL.
+func PriceScheduleInterval() time.Duration {
+	return getDuration("PRICE_SCHEDULE_INTERVAL", time.Minute)
+}
+
+// ReservationTTL is how long stock stays held for a pending checkout,
+// configured with RESERVATION_TTL (e.g. "15m").
+func ReservationTTL() time.Duration {
+	return getDuration("RESERVATION_TTL", 15*time.Minute)
+}
+
+// ReservationSweepInterval is how often expired reservations are released,
+// configured with RESERVATION_SWEEP_INTERVAL.
+func ReservationSweepInterval() time.Duration {
+	return getDuration("RESERVATION_SWEEP_INTERVAL", time.Minute)
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+	return duration
+}
+
+// getDate reads a YYYY-MM-DD date, returning the zero time when it is unset
+// or invalid.
+func getDate(key string) time.Time {
+	date, err := time.Parse(time.DateOnly, os.Getenv(key))
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
+
+func getInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
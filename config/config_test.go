@@ -0,0 +1,40 @@
+package config
+
+import (
+	"context"
+	"e-commerce/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// statementLog records the SQL gorm would run, so migrations can be checked
+// without a database.
+type statementLog struct {
+	logger.Interface
+	statements []string
+}
+
+func (sl *statementLog) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	sl.statements = append(sl.statements, sql)
+}
+
+func TestUniqueSKUsLetDeletedVariantSKUsBeReused(t *testing.T) {
+	statements := &statementLog{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1"), &gorm.Config{DisableAutomaticPing: true, DryRun: true, Logger: statements})
+	require.NoError(t, err)
+
+	require.NoError(t, uniqueSKUs(db, &models.ProductVariant{}, "product_variants", "idx_product_variants_sku"))
+
+	require.Len(t, statements.statements, 3)
+	// A deleted variant sharing the SKU of a live one isn't a duplicate
+	assert.Contains(t, statements.statements[0], `"product_variants"."deleted_at" IS NULL`)
+	assert.Equal(t, "DROP INDEX IF EXISTS idx_product_variants_sku", statements.statements[1])
+	assert.Equal(t, "CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_sku_unique ON product_variants (sku) WHERE deleted_at IS NULL AND sku <> ''", statements.statements[2])
+}
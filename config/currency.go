@@ -0,0 +1,17 @@
+package config
+
+import (
+	"e-commerce/money"
+	"log"
+	"os"
+)
+
+// NewRateProvider reads exchange rates against the rupiah from
+// EXCHANGE_RATES, e.g. "USD=0.000063,SGD=0.000085".
+func NewRateProvider() money.RateProvider {
+	rates, err := money.ParseStaticRates(money.BaseCurrency, os.Getenv("EXCHANGE_RATES"))
+	if err != nil {
+		log.Fatal("Invalid EXCHANGE_RATES ", err)
+	}
+	return rates
+}
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"e-commerce/i18n"
+	"log"
+)
+
+// DefaultLocale is the language of responses to clients whose
+// Accept-Language names no supported one, configured with DEFAULT_LOCALE
+// ("en" or "id").
+func DefaultLocale() string {
+	locale := getEnv("DEFAULT_LOCALE", i18n.English)
+	if !i18n.Supported(locale) {
+		log.Fatal("Invalid DEFAULT_LOCALE ", locale)
+	}
+	return locale
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"e-commerce/invoice"
+	"os"
+	"strings"
+)
+
+// InvoiceSeller is the seller printed on invoices. SELLER_ADDRESS lines
+// are separated by "|" and SELLER_NPWP is the seller's tax ID.
+func InvoiceSeller() invoice.Party {
+	var address []string
+	for _, line := range strings.Split(os.Getenv("SELLER_ADDRESS"), "|") {
+		if line = strings.TrimSpace(line); line != "" {
+			address = append(address, line)
+		}
+	}
+	return invoice.Party{
+		Name:    getEnv("SELLER_NAME", "E-Commerce"),
+		Email:   os.Getenv("SELLER_EMAIL"),
+		Phone:   os.Getenv("SELLER_PHONE"),
+		Address: address,
+		TaxID:   os.Getenv("SELLER_NPWP"),
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"e-commerce/entity"
+	"e-commerce/models"
+	"e-commerce/notify"
+	"e-commerce/services"
+	"e-commerce/storage"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobSchedulerInterval is how often due scheduled jobs are looked for,
+// configured with JOB_SCHEDULER_INTERVAL.
+func JobSchedulerInterval() time.Duration {
+	return getDuration("JOB_SCHEDULER_INTERVAL", time.Minute)
+}
+
+// JobInstanceID identifies this process when claiming scheduled jobs.
+func JobInstanceID() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// NewExportStore is where scheduled jobs write their files: the directory
+// EXPORT_DIR when set, otherwise the blob store from NewBlobStore.
+func NewExportStore() storage.BlobStore {
+	if dir := os.Getenv("EXPORT_DIR"); dir != "" {
+		store, err := storage.NewLocalStore(dir)
+		if err != nil {
+			log.Fatal("Error preparing export directory", err)
+		}
+		return store
+	}
+	return NewBlobStore()
+}
+
+// NewMailer builds the mailer selected by MAILER ("log", the default, or
+// "smtp"), sharing the SMTP settings of the email notifier.
+func NewMailer() notify.Mailer {
+	if getEnv("MAILER", "log") == "smtp" {
+		return newSMTPMailer()
+	}
+	return notify.LogMailer{}
+}
+
+func newSMTPMailer() *notify.SMTPMailer {
+	return notify.NewSMTPMailer(
+		getEnv("SMTP_HOST", "localhost"),
+		getEnv("SMTP_PORT", "25"),
+		os.Getenv("SMTP_USERNAME"),
+		os.Getenv("SMTP_PASSWORD"),
+		getEnv("SMTP_FROM", "no-reply@example.com"),
+	)
+}
+
+// seedSalesReportJob creates the daily-sales-report job mailing yesterday's
+// sales to SALES_REPORT_RECIPIENTS (comma separated) at SALES_REPORT_SCHEDULE,
+// unless it already exists.
+func seedSalesReportJob(db *gorm.DB) error {
+	recipients := os.Getenv("SALES_REPORT_RECIPIENTS")
+	if recipients == "" {
+		return nil
+	}
+	schedule := getEnv("SALES_REPORT_SCHEDULE", "0 1 * * *")
+	nextRunAt, err := services.NextJobRun(schedule, time.Now())
+	if err != nil {
+		return err
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ScheduledJob{
+		Name:       "daily-sales-report",
+		Type:       entity.JobSalesReport,
+		Schedule:   schedule,
+		Recipients: recipients,
+		PeriodDays: 1,
+		Enabled:    true,
+		NextRunAt:  nextRunAt,
+	}).Error
+}
@@ -0,0 +1,13 @@
+package config
+
+import (
+	"e-commerce/logging"
+	"log/slog"
+	"os"
+)
+
+// NewLogger builds the JSON logger writing to stdout at LOG_LEVEL ("debug",
+// "info", the default, "warn" or "error").
+func NewLogger() *slog.Logger {
+	return logging.New(os.Stdout, logging.ParseLevel(os.Getenv("LOG_LEVEL")))
+}
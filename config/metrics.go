@@ -0,0 +1,8 @@
+package config
+
+import "os"
+
+// MetricsToken protects /metrics when set, configured with METRICS_TOKEN.
+func MetricsToken() string {
+	return os.Getenv("METRICS_TOKEN")
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"e-commerce/notify"
+	"os"
+	"time"
+)
+
+// NewNotifier builds the notifier selected by NOTIFIER ("log", the
+// default, "email" or "webhook").
+func NewNotifier() notify.Notifier {
+	switch getEnv("NOTIFIER", "log") {
+	case "email":
+		return notify.NewEmailNotifier(newSMTPMailer())
+	case "webhook":
+		return notify.NewWebhookNotifier(os.Getenv("NOTIFY_WEBHOOK_URL"))
+	default:
+		return notify.LogNotifier{}
+	}
+}
+
+// StockAlertRecipient receives low and out of stock alerts.
+func StockAlertRecipient() string {
+	return getEnv("STOCK_ALERT_RECIPIENT", "admin@example.com")
+}
+
+// StockAlertInterval is how often pending stock events are delivered.
+func StockAlertInterval() time.Duration {
+	return getDuration("STOCK_ALERT_INTERVAL", 30*time.Second)
+}
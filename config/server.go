@@ -0,0 +1,52 @@
+package config
+
+import (
+	"e-commerce/router"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// NewHTTPServer serves handler on PORT (default 8080) with the timeouts set
+// by HTTP_READ_TIMEOUT, HTTP_READ_HEADER_TIMEOUT, HTTP_WRITE_TIMEOUT and
+// HTTP_IDLE_TIMEOUT.
+func NewHTTPServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":" + getEnv("PORT", "8080"),
+		Handler:           handler,
+		ReadTimeout:       getDuration("HTTP_READ_TIMEOUT", 15*time.Second),
+		ReadHeaderTimeout: getDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		WriteTimeout:      getDuration("HTTP_WRITE_TIMEOUT", 60*time.Second),
+		IdleTimeout:       getDuration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+	}
+}
+
+// ShutdownTimeout is how long in-flight requests get to finish after
+// SIGTERM, configured with SHUTDOWN_TIMEOUT.
+func ShutdownTimeout() time.Duration {
+	return getDuration("SHUTDOWN_TIMEOUT", 30*time.Second)
+}
+
+// RequestTimeout is the deadline for handling one request, configured with
+// REQUEST_TIMEOUT.
+func RequestTimeout() time.Duration {
+	return getDuration("REQUEST_TIMEOUT", 30*time.Second)
+}
+
+// APIOptions serves /api/v2 next to /api/v1 when API_V2_ENABLED is true.
+// Setting API_V1_DEPRECATED_AT, and optionally API_V1_SUNSET, to a
+// YYYY-MM-DD date marks v1 responses as deprecated.
+func APIOptions() router.Options {
+	v2, _ := strconv.ParseBool(os.Getenv("API_V2_ENABLED"))
+	opts := router.Options{V2: v2}
+	since := getDate("API_V1_DEPRECATED_AT")
+	if since.IsZero() {
+		return opts
+	}
+	opts.V1Deprecation = &router.Deprecation{Since: since, Sunset: getDate("API_V1_SUNSET")}
+	if v2 {
+		opts.V1Deprecation.Successor = router.V2
+	}
+	return opts
+}
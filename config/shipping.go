@@ -0,0 +1,54 @@
+package config
+
+import (
+	"e-commerce/services"
+	"encoding/json"
+	"log"
+	"os"
+)
+
+// defaultShippingZones charges less within Java than to the other islands.
+var defaultShippingZones = services.WeightZoneShipping{
+	Provinces: map[string]string{
+		"DKI Jakarta":   "jawa",
+		"Jawa Barat":    "jawa",
+		"Jawa Tengah":   "jawa",
+		"Jawa Timur":    "jawa",
+		"DI Yogyakarta": "jawa",
+		"Banten":        "jawa",
+	},
+	Zones: map[string]services.ZoneRate{
+		"jawa":      {FirstKg: 10000, NextKg: 6000},
+		"luar_jawa": {FirstKg: 25000, NextKg: 15000},
+	},
+	DefaultZone: "luar_jawa",
+}
+
+// NewShippingCalculator builds the calculator selected by SHIPPING_METHOD.
+// "flat", the default, charges SHIPPING_FLAT_RATE, free from
+// SHIPPING_FREE_ABOVE. "zone" charges by weight and province using the
+// table in the JSON file SHIPPING_ZONES_FILE, or a built-in table.
+func NewShippingCalculator() services.ShippingCalculator {
+	switch getEnv("SHIPPING_METHOD", "flat") {
+	case "zone":
+		path := os.Getenv("SHIPPING_ZONES_FILE")
+		if path == "" {
+			return defaultShippingZones
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatal("Error opening SHIPPING_ZONES_FILE ", err)
+		}
+		defer file.Close()
+		var zones services.WeightZoneShipping
+		if err := json.NewDecoder(file).Decode(&zones); err != nil {
+			log.Fatal("Error reading SHIPPING_ZONES_FILE ", err)
+		}
+		return zones
+	default:
+		return services.FlatRateShipping{
+			Rate:      getInt("SHIPPING_FLAT_RATE", 15000),
+			FreeAbove: getInt("SHIPPING_FREE_ABOVE", 0),
+		}
+	}
+}
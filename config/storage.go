@@ -0,0 +1,53 @@
+package config
+
+import (
+	"e-commerce/services"
+	"e-commerce/storage"
+	"log"
+	"os"
+	"time"
+)
+
+// NewBlobStore builds the image storage selected by STORAGE_DRIVER
+// ("local", the default, or "s3").
+func NewBlobStore() storage.BlobStore {
+	switch getEnv("STORAGE_DRIVER", "local") {
+	case "s3":
+		return storage.NewS3Store(
+			getEnv("S3_ENDPOINT", "http://localhost:9000"),
+			getEnv("S3_BUCKET", "e-commerce"),
+			getEnv("S3_REGION", "us-east-1"),
+			os.Getenv("S3_ACCESS_KEY"),
+			os.Getenv("S3_SECRET_KEY"),
+		)
+	default:
+		store, err := storage.NewLocalStore(getEnv("STORAGE_DIR", "uploads"))
+		if err != nil {
+			log.Fatal("Error preparing storage directory", err)
+		}
+		return store
+	}
+}
+
+// NewURLSigner signs the image URLs served under /images. IMAGE_URL_SECRET
+// is required, a guessable default would let anyone sign their own URLs.
+func NewURLSigner() storage.URLSigner {
+	secret := os.Getenv("IMAGE_URL_SECRET")
+	if secret == "" {
+		log.Fatal("IMAGE_URL_SECRET is required")
+	}
+	return storage.URLSigner{
+		BaseURL: getEnv("PUBLIC_URL", "http://localhost:8080") + "/images",
+		Secret:  []byte(secret),
+		TTL:     15 * time.Minute,
+	}
+}
+
+// ImageLimits bounds the dimensions of uploaded product images.
+func ImageLimits() services.ImageLimits {
+	return services.ImageLimits{
+		MaxWidth:  getInt("IMAGE_MAX_WIDTH", 6000),
+		MaxHeight: getInt("IMAGE_MAX_HEIGHT", 6000),
+		MaxPixels: getInt("IMAGE_MAX_PIXELS", 25_000_000),
+	}
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"e-commerce/services"
+	"log"
+	"math"
+	"os"
+	"strconv"
+)
+
+// TaxConfig reads the tax settings. TAX_RATE is the default rate in percent
+// (11 for PPN), TAX_NAME is shown on bills and TAX_INCLUDED=true means
+// product prices already include the tax.
+func TaxConfig() services.TaxConfig {
+	rate := 1100
+	if value := os.Getenv("TAX_RATE"); value != "" {
+		percent, err := strconv.ParseFloat(value, 64)
+		rate = int(math.Round(percent * 100))
+		if err != nil || services.ValidateTaxRate(rate) != nil {
+			log.Fatal("Invalid TAX_RATE ", value)
+		}
+	}
+	included, _ := strconv.ParseBool(os.Getenv("TAX_INCLUDED"))
+	return services.TaxConfig{
+		Name:             getEnv("TAX_NAME", "PPN"),
+		DefaultRate:      rate,
+		PricesIncludeTax: included,
+	}
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"e-commerce/tracing"
+	"os"
+	"strconv"
+)
+
+// TracingConfig selects the trace exporter with OTEL_TRACES_EXPORTER
+// ("none", the default, "otlp" or "console" for stdout) and the sampled
+// fraction of traces with OTEL_TRACES_SAMPLER_ARG (default 1).
+func TracingConfig() tracing.Config {
+	exporter := getEnv("OTEL_TRACES_EXPORTER", tracing.ExporterNone)
+	if exporter == "console" {
+		exporter = tracing.ExporterStdout
+	}
+	ratio, err := strconv.ParseFloat(os.Getenv("OTEL_TRACES_SAMPLER_ARG"), 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		ratio = 1
+	}
+	return tracing.Config{
+		ServiceName: getEnv("OTEL_SERVICE_NAME", "e-commerce"),
+		Exporter:    exporter,
+		SampleRatio: ratio,
+	}
+}
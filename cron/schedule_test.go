@@ -0,0 +1,43 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC) // a Friday
+
+func next(t *testing.T, expr string, from time.Time) time.Time {
+	schedule, err := Parse(expr)
+	assert.NoError(t, err)
+	return schedule.Next(from)
+}
+
+func TestNext(t *testing.T) {
+	assert.Equal(t, time.Date(2024, 3, 15, 10, 31, 0, 0, time.UTC), next(t, "* * * * *", start))
+	assert.Equal(t, time.Date(2024, 3, 16, 1, 0, 0, 0, time.UTC), next(t, "0 1 * * *", start))
+	assert.Equal(t, time.Date(2024, 3, 15, 10, 45, 0, 0, time.UTC), next(t, "*/15 * * * *", start))
+	assert.Equal(t, time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC), next(t, "0 9 * * 1-5", time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), next(t, "@monthly", start))
+	assert.Equal(t, time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC), next(t, "0 0 * * 7", start))
+	assert.Equal(t, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC), next(t, "0 0 29 2 *", start))
+	// A run at exactly the scheduled minute moves on to the next one
+	assert.Equal(t, time.Date(2024, 3, 16, 10, 30, 0, 0, time.UTC), next(t, "30 10 * * *", start))
+}
+
+func TestNextEitherDayField(t *testing.T) {
+	// The 1st of the month or any Monday
+	assert.Equal(t, time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC), next(t, "0 0 1 * 1", start))
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), next(t, "0 0 1 * 1", time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+	assert.Error(t, Validate("0 0 30 2 *"))
+	assert.NoError(t, Validate("0 1 * * *"))
+}
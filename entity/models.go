@@ -1,5 +1,10 @@
 package entity
 
+import (
+	"e-commerce/money"
+	"time"
+)
+
 type User struct {
 	ID                 uint
 	FullName           string
@@ -10,25 +15,289 @@ type User struct {
 	TransactionHistory []TransactionHistory
 }
 
+type Address struct {
+	ID            uint
+	UserID        uint
+	Label         string
+	RecipientName string
+	Phone         string
+	Street        string
+	City          string
+	Province      string
+	PostalCode    string
+	IsDefault     bool
+}
+
 type Category struct {
 	ID                uint
 	Type              string
 	SoldProductAmount int
+	TaxRate           *int
 	Products          []Product
 }
+
+// CategoryDeletion says what happens to the products of a deleted
+// category. Cascade deletes them and ReassignTo moves them to another
+// category. With neither, only a category without products is deleted.
+type CategoryDeletion struct {
+	Cascade    bool
+	ReassignTo uint
+}
+
 type Product struct {
 	ID                 string
+	SKU                string
 	Title              string
 	Price              int
 	Stock              int
 	CategoryID         int
+	ReorderThreshold   int
+	Weight             int
+	Variants           []ProductVariant
+	Prices             []ProductPrice
 	TransactionHistory []TransactionHistory
 }
+
+type ProductPrice struct {
+	ProductID uint
+	Price     money.Money
+}
+type ProductVariant struct {
+	ID        uint
+	ProductID uint
+	SKU       string
+	Size      string
+	Color     string
+	Price     *int
+	Stock     int
+}
 type TransactionHistory struct {
-	ID         string
-	ProductID  uint
+	ID              string
+	ProductID       uint
+	VariantID       *uint
+	UserID          uint
+	Quantity        int
+	Subtotal        int
+	Discount        int
+	CouponID        *uint
+	TaxName         string
+	TaxRate         int
+	TaxBase         int
+	Tax             int
+	TaxIncluded     bool
+	ShippingCost    int
+	AddressID       *uint
+	ShippingAddress ShippingAddress
+	TotalPrice      int
+	CreatedAt       time.Time
+	Product         Product
+	Variant         *ProductVariant
+}
+
+type ShippingAddress struct {
+	RecipientName string
+	Phone         string
+	Street        string
+	City          string
+	Province      string
+	PostalCode    string
+}
+
+// TransactionFilter selects a page of transactions. Zero values don't
+// filter. Sort is a column name and Descending reverses it.
+type TransactionFilter struct {
 	UserID     uint
-	Quantity   int
-	TotalPrice int
-	Product    Product
+	ProductID  uint
+	CategoryID uint
+	From       *time.Time
+	To         *time.Time
+	MinTotal   *int
+	MaxTotal   *int
+	Sort       string
+	Descending bool
+	Page       int
+	PageSize   int
+}
+type InventoryMovement struct {
+	ID            uint
+	ProductID     uint
+	VariantID     *uint
+	UserID        uint
+	TransactionID *uint
+	Type          string
+	Quantity      int
+	StockAfter    int
+	Note          string
+	CreatedAt     time.Time
+}
+
+// Reservation statuses.
+const (
+	ReservationActive   = "active"
+	ReservationConsumed = "consumed"
+	ReservationReleased = "released"
+	ReservationExpired  = "expired"
+)
+
+type StockEvent struct {
+	ID        uint
+	ProductID uint
+	VariantID *uint
+	Type      string
+	Stock     int
+	Threshold int
+	Attempts  int
+	CreatedAt time.Time
+}
+
+type RestockSubscription struct {
+	ID        uint
+	UserID    uint
+	Email     string
+	FullName  string
+	ProductID uint
+	VariantID *uint
 }
+
+// Stock event types.
+const (
+	StockEventLowStock    = "low_stock"
+	StockEventOutOfStock  = "out_of_stock"
+	StockEventBackInStock = "back_in_stock"
+)
+
+// MaxStockEventAttempts is how many times delivering a stock event is tried
+// before the dispatcher gives up on it.
+const MaxStockEventAttempts = 5
+
+// StockEventClaimTimeout is how long a dispatcher may hold a stock event.
+// A claim older than that is taken to belong to a dispatcher that stopped
+// mid-delivery, and the event can be claimed again.
+const StockEventClaimTimeout = 5 * time.Minute
+
+type Coupon struct {
+	ID             uint
+	Code           string
+	Type           string
+	Value          int
+	MinSpend       int
+	StartsAt       *time.Time
+	EndsAt         *time.Time
+	MaxRedemptions int
+	MaxPerUser     int
+	ProductID      *uint
+	CategoryID     *uint
+	Active         bool
+}
+
+// Coupon types.
+const (
+	CouponPercentage   = "percentage"
+	CouponFixedAmount  = "fixed_amount"
+	CouponFreeQuantity = "free_quantity"
+)
+
+type PriceHistory struct {
+	ID                uint
+	ProductID         uint
+	UserID            uint
+	ScheduledChangeID *uint
+	OldPrice          int
+	NewPrice          int
+	Reason            string
+	CreatedAt         time.Time
+}
+
+// Price history reasons.
+const (
+	PriceReasonInitial       = "initial"
+	PriceReasonManual        = "manual"
+	PriceReasonScheduleStart = "schedule_start"
+	PriceReasonScheduleEnd   = "schedule_end"
+)
+
+type ScheduledPriceChange struct {
+	ID          uint
+	ProductID   uint
+	UserID      uint
+	Price       int
+	StartsAt    time.Time
+	EndsAt      *time.Time
+	RevertPrice *int
+	Status      string
+}
+
+// Scheduled price change statuses.
+const (
+	PriceChangePending   = "pending"
+	PriceChangeActive    = "active"
+	PriceChangeCompleted = "completed"
+	PriceChangeCancelled = "cancelled"
+)
+
+// ReportRange selects the transactions created from From up to, but not
+// including, To.
+type ReportRange struct {
+	From time.Time
+	To   time.Time
+}
+
+type RevenuePoint struct {
+	Period  time.Time
+	Orders  int64
+	Units   int64
+	Revenue int64
+}
+
+// RankedItem is a product, category or customer in a top list.
+type RankedItem struct {
+	ID      uint
+	Name    string
+	Orders  int64
+	Units   int64
+	Revenue int64
+}
+
+type SalesSummary struct {
+	Orders            int64
+	Units             int64
+	Revenue           int64
+	Discount          int64
+	Tax               int64
+	Shipping          int64
+	AverageOrderValue int64
+}
+
+// Report granularities.
+const (
+	ReportDaily   = "day"
+	ReportWeekly  = "week"
+	ReportMonthly = "month"
+)
+
+type ScheduledJob struct {
+	ID         uint
+	Name       string
+	Type       string
+	Schedule   string
+	Recipients []string
+	PeriodDays int
+	Enabled    bool
+	NextRunAt  time.Time
+	LastRunAt  *time.Time
+	LastStatus string
+	LastError  string
+	LastOutput string
+}
+
+// Scheduled job types.
+const (
+	JobSalesReport        = "sales_report"
+	JobTransactionsExport = "transactions_export"
+)
+
+// Scheduled job run statuses.
+const (
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/i18n"
+	"e-commerce/models"
+	"e-commerce/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AddressInput is the request body for creating and updating addresses.
+type AddressInput struct {
+	Label         string `json:"label"`
+	RecipientName string `json:"recipient_name"`
+	Phone         string `json:"phone"`
+	Street        string `json:"street"`
+	City          string `json:"city"`
+	Province      string `json:"province"`
+	PostalCode    string `json:"postal_code"`
+	IsDefault     bool   `json:"is_default"`
+}
+
+// @Summary Get my addresses
+// @Description List the address book of the authenticated user, default address first
+// @Tags Addresses
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} models.Address "Addresses"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /addresses [get]
+func GetMyAddresses(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		addresses := []models.Address{}
+		if err := db.Where("user_id = ?", actingUserID(c)).Order("is_default DESC, id").Find(&addresses).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, addresses)
+	}
+}
+
+// @Summary Add an address
+// @Description Add an address to the authenticated user's address book. The first address becomes the default
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param address body AddressInput true "Address"
+// @Success 201 {object} models.Address "Address created"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /addresses [post]
+func CreateAddress(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var userInput AddressInput
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+		newAddress := models.Address{UserID: actingUserID(c)}
+		applyAddressInput(&newAddress, userInput)
+		if err := services.ValidateAddress(addressEntity(newAddress)); err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var count int64
+			if err := tx.Model(&models.Address{}).Where("user_id = ?", newAddress.UserID).Count(&count).Error; err != nil {
+				return err
+			}
+			newAddress.IsDefault = newAddress.IsDefault || count == 0
+			if err := tx.Create(&newAddress).Error; err != nil {
+				return err
+			}
+			return keepSingleDefault(tx, newAddress)
+		})
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, newAddress)
+	}
+}
+
+// @Summary Update an address
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param addressId path integer true "Address ID"
+// @Param address body AddressInput true "Address"
+// @Success 200 {object} models.Address "Updated address"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 404 {object} apperror.Problem "Address not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /addresses/{addressId} [put]
+func UpdateAddress(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingAddress models.Address
+		if err := db.Where("id = ? AND user_id = ?", c.Param("addressId"), actingUserID(c)).First(&existingAddress).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("address_not_found", "Address not found"))
+			return
+		}
+		var userInput AddressInput
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+		wasDefault := existingAddress.IsDefault
+		applyAddressInput(&existingAddress, userInput)
+		// The default can only move to another address, not be switched off
+		existingAddress.IsDefault = existingAddress.IsDefault || wasDefault
+		if err := services.ValidateAddress(addressEntity(existingAddress)); err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&existingAddress).Error; err != nil {
+				return err
+			}
+			return keepSingleDefault(tx, existingAddress)
+		})
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, existingAddress)
+	}
+}
+
+// @Summary Delete an address
+// @Description Delete an address from the address book. When it was the default, the oldest remaining address becomes the default
+// @Tags Addresses
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param addressId path integer true "Address ID"
+// @Success 200 {object} SuccessResponse "Address deleted"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 404 {object} apperror.Problem "Address not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /addresses/{addressId} [delete]
+func DeleteAddress(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingAddress models.Address
+		if err := db.Where("id = ? AND user_id = ?", c.Param("addressId"), actingUserID(c)).First(&existingAddress).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("address_not_found", "Address not found"))
+			return
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Delete(&existingAddress).Error; err != nil {
+				return err
+			}
+			if !existingAddress.IsDefault {
+				return nil
+			}
+			var next models.Address
+			err := tx.Where("user_id = ?", existingAddress.UserID).Order("id").First(&next).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return tx.Model(&next).Update("is_default", true).Error
+		})
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "address_deleted", nil)})
+	}
+}
+
+func applyAddressInput(address *models.Address, input AddressInput) {
+	address.Label = input.Label
+	address.RecipientName = input.RecipientName
+	address.Phone = input.Phone
+	address.Street = input.Street
+	address.City = input.City
+	address.Province = input.Province
+	address.PostalCode = input.PostalCode
+	address.IsDefault = input.IsDefault
+}
+
+// keepSingleDefault clears the default flag on the user's other addresses
+// when address is the default.
+func keepSingleDefault(tx *gorm.DB, address models.Address) error {
+	if !address.IsDefault {
+		return nil
+	}
+	return tx.Model(&models.Address{}).
+		Where("user_id = ? AND id <> ? AND is_default", address.UserID, address.ID).
+		Update("is_default", false).Error
+}
+
+// shippingAddress picks the address a purchase is shipped to: addressID
+// when given, otherwise the user's default address.
+func shippingAddress(db *gorm.DB, userID, addressID uint) (*models.Address, error) {
+	var address models.Address
+	query := db.Where("user_id = ?", userID)
+	if addressID != 0 {
+		query = query.Where("id = ?", addressID)
+	} else {
+		query = query.Where("is_default")
+	}
+	if err := query.First(&address).Error; err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func addressEntity(address models.Address) entity.Address {
+	return entity.Address{
+		ID:            address.ID,
+		UserID:        address.UserID,
+		Label:         address.Label,
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		Street:        address.Street,
+		City:          address.City,
+		Province:      address.Province,
+		PostalCode:    address.PostalCode,
+		IsDefault:     address.IsDefault,
+	}
+}
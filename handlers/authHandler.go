@@ -1,25 +1,36 @@
 package handlers
 
 import (
+	"e-commerce/apperror"
 	"e-commerce/auth"
 	"e-commerce/helpers"
+	"e-commerce/metrics"
 	"e-commerce/models"
+	"e-commerce/tracing"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-// ErrorResponse represents an error response in the API.
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-
 // SuccessResponse represents a success response in the API.
 type SuccessResponse struct {
 	Message string `json:"message"`
 }
 
+// RegisterInput is the request body for registering a customer.
+type RegisterInput struct {
+	Email    string `json:"email" binding:"required,email"`
+	FullName string `json:"full_name" binding:"required"`
+	Password string `json:"password" binding:"required,password"`
+}
+
+// LoginInput is the request body for logging in.
+type LoginInput struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
 // @Summary Register a new user
 // @Produce json
 // @Consumes json
@@ -27,57 +38,30 @@ type SuccessResponse struct {
 // @Param full_name body string true "Full Name"
 // @Param password body string true "Password"
 // @Success 201 {object} models.User "User registered successfully"
-// @Failure 400 {object} ErrorResponse "Bad Request"
-// @Failure 409 {object} ErrorResponse "Email already exists"
-// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 409 {object} apperror.Problem "Email already exists"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
 // @Router /users/register [post]
 func Register(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Create a struct to hold only the necessary fields
-		var newUserInput struct {
-			Email    string `json:"email"`
-			FullName string `json:"full_name"`
-			Password string `json:"password"`
-		}
-
-		// Bind only the specified fields from the JSON request
+		db := db.WithContext(c.Request.Context())
+		var newUserInput RegisterInput
 		if err := c.ShouldBindJSON(&newUserInput); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-
-		// Check for empty fields using if statements
-		if newUserInput.FullName == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Full Name cannot be empty"})
-			return
-		}
-
-		if newUserInput.Password == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Password cannot be empty"})
-			return
-		}
-
-		// Check if the password length is less than 6
-		if len(newUserInput.Password) < 6 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Password must be at least 6 characters long"})
-			return
-		}
-
-		// Check if the email is in a valid format
-		if err := helpers.IsValidEmail(newUserInput.Email); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			apperror.Respond(c, apperror.InvalidBody(err))
 			return
 		}
 
 		// Ensure the email is unique before attempting to create the user
 		var existingUser models.User
 		if err := db.Where("email = ?", newUserInput.Email).First(&existingUser).Error; err == nil {
-			c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+			apperror.Respond(c, apperror.Conflict("email_exists", "Email already exists"))
 			return
 		}
+		_, span := tracing.Start(c.Request.Context(), "helpers.HashPassword")
 		hashedPassword, err := helpers.HashPassword(newUserInput.Password)
+		span.End()
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+			apperror.Respond(c, apperror.Internal(err, "password_hash_failed", "Failed to hash password"))
 			return
 		}
 		// If all checks pass, create the new user in the database
@@ -89,7 +73,7 @@ func Register(db *gorm.DB) gin.HandlerFunc {
 			Role:     "customer",
 		}
 		if err := db.Create(&newUser).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+			apperror.Respond(c, apperror.Internal(err, "user_create_failed", "Failed to create user"))
 			return
 		}
 
@@ -103,28 +87,17 @@ func Register(db *gorm.DB) gin.HandlerFunc {
 // @Param email body string true "Email"
 // @Param password body string true "Password"
 // @Success 200 {string} Token "API token for authentication"
-// @Failure 400 {object} ErrorResponse "Bad Request"
-// @Failure 401 {object} ErrorResponse "Unauthorized"
-// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
 // @Router /users/login [post]
 
 func Login(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var user models.User
+		db := db.WithContext(c.Request.Context())
+		var user LoginInput
 		if err := c.ShouldBindJSON(&user); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-
-		// Check for empty email and password
-		if user.Email == "" || user.Password == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Email and password cannot be empty"})
-			return
-		}
-
-		// Check if the email is in a valid format
-		if err := helpers.IsValidEmail(user.Email); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			apperror.Respond(c, apperror.InvalidBody(err))
 			return
 		}
 
@@ -132,20 +105,25 @@ func Login(db *gorm.DB) gin.HandlerFunc {
 		var foundUser models.User
 		result := db.Where("email = ?", user.Email).First(&foundUser)
 		if result.Error != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+			metrics.FailedLogins.Inc()
+			apperror.Respond(c, apperror.Unauthorized("invalid_credentials", "Invalid email or password"))
 			return
 		}
 
 		// Compare the provided password with the hashed password
-		if err := helpers.ComparePassword(foundUser.Password, user.Password); err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		_, span := tracing.Start(c.Request.Context(), "helpers.ComparePassword")
+		err := helpers.ComparePassword(foundUser.Password, user.Password)
+		span.End()
+		if err != nil {
+			metrics.FailedLogins.Inc()
+			apperror.Respond(c, apperror.Unauthorized("invalid_credentials", "Invalid email or password"))
 			return
 		}
 
 		// Convert user.ID to uint before passing it to GenerateToken
 		token, err := auth.GenerateToken(user.Email, foundUser.Role, uint(foundUser.ID))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while generating token"})
+			apperror.Respond(c, apperror.Internal(err, "token_generation_failed", "Error while generating token"))
 			return
 		}
 
@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"e-commerce/apperror"
+	"e-commerce/i18n"
 	"e-commerce/models"
-	"errors"
+	"e-commerce/repository"
+	"e-commerce/services"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -15,39 +19,49 @@ import (
 // @Consumes json
 // @Param Authorization header string true "Bearer token for authentication"
 // @Param type body string true "Category type"
+// @Param tax_rate body int false "Tax rate in hundredths of a percent (1100 is 11%), the default rate is used when empty"
 // @Success 201 {object} models.Category "Category created successfully"
-// @Failure 400 {object} ErrorResponse "Bad request"
-// @Failure 401 {object} ErrorResponse "Unauthorized"
-// @Failure 403 {object} ErrorResponse "Forbidden"
-// @Failure 409 {object} ErrorResponse "Conflict"
-// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 400 {object} apperror.Problem "Bad request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 409 {object} apperror.Problem "Conflict"
+// @Failure 500 {object} apperror.Problem "Internal server error"
 // @Router /categories [post]
 func CreateCategory(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
 		var userInput struct {
-			Type string `json:"type"`
+			Type    string `json:"type"`
+			TaxRate *int   `json:"tax_rate"`
 		}
 		// Bind only the specified fields from the JSON request
 		if err := c.ShouldBindJSON(&userInput); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			apperror.Respond(c, apperror.InvalidBody(err))
 			return
 		}
 		if userInput.Type == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Type cannot be empty"})
+			apperror.Respond(c, apperror.Validation("category_type_required", "Type cannot be empty"))
 			return
 		}
+		if userInput.TaxRate != nil {
+			if err := services.ValidateTaxRate(*userInput.TaxRate); err != nil {
+				apperror.Respond(c, apperror.Invalid(err))
+				return
+			}
+		}
 
 		var existingCategory models.Category
 		if err := db.Where("type = ?", userInput.Type).First(&existingCategory).Error; err == nil {
-			c.JSON(http.StatusConflict, gin.H{"error": "Type already exists!"})
+			apperror.Respond(c, apperror.Conflict("category_type_exists", "Type already exists!"))
 			return
 		}
 		newCategory := models.Category{
 			Type:              userInput.Type,
 			SoldProductAmount: 0,
+			TaxRate:           userInput.TaxRate,
 		}
 		if err := db.Create(&newCategory).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create category"})
+			apperror.Respond(c, apperror.Internal(err, "category_create_failed", "Failed to create category"))
 			return
 		}
 		c.JSON(http.StatusCreated, newCategory)
@@ -59,10 +73,11 @@ func CreateCategory(db *gorm.DB) gin.HandlerFunc {
 // @Produce json
 // @Param Authorization header string true "Bearer token for authentication"
 // @Success 200 {array} models.Category "List of categories"
-// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
 // @Router /categories [get]
 func GetCategories(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
 		var categories []models.Category
 		db.Preload("Products").Find(&categories)
 		if len(categories) == 0 {
@@ -80,40 +95,50 @@ func GetCategories(db *gorm.DB) gin.HandlerFunc {
 // @Param Authorization header string true "Bearer token for authentication"
 // @Param id path int true "Category ID" Format(int64)
 // @Param type body string true "Type"
+// @Param tax_rate body int false "Tax rate in hundredths of a percent (1100 is 11%), unchanged when empty"
 // @Success 200 {object} models.Category "Updated category"
-// @Failure 400 {object} ErrorResponse "Bad Request"
-// @Failure 401 {object} ErrorResponse "Unauthorized"
-// @Failure 404 {object} ErrorResponse "Category not found"
-// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 404 {object} apperror.Problem "Category not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
 // @Router /categories/{id} [patch]
 func UpdateCategory(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
 		id := c.Param("categoryId")
 
 		var existingCategory models.Category
 		if err := db.Where("id = ?", id).First(&existingCategory).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			apperror.Respond(c, apperror.NotFound("category_not_found", "Category not found"))
 			return
 		}
 
 		var userInput struct {
-			Type string `json:"type"`
+			Type    string `json:"type"`
+			TaxRate *int   `json:"tax_rate"`
 		}
 
 		// Bind only the specified fields from the JSON request
 		if err := c.ShouldBindJSON(&userInput); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			apperror.Respond(c, apperror.InvalidBody(err))
 			return
 		}
 		if userInput.Type == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Type can't be empty"})
+			apperror.Respond(c, apperror.Validation("category_type_required", "Type can't be empty"))
 			return
 		}
+		if userInput.TaxRate != nil {
+			if err := services.ValidateTaxRate(*userInput.TaxRate); err != nil {
+				apperror.Respond(c, apperror.Invalid(err))
+				return
+			}
+			existingCategory.TaxRate = userInput.TaxRate
+		}
 
 		existingCategory.Type = userInput.Type
 		// Save the changes to the database
 		if err := db.Save(&existingCategory).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			apperror.Respond(c, err)
 			return
 		}
 
@@ -123,32 +148,36 @@ func UpdateCategory(db *gorm.DB) gin.HandlerFunc {
 
 // DeleteCategory Deletes a category by ID
 // @Summary Deletes a category by ID
+// @Description By default a category that still has products is not deleted. Use mode=cascade to delete its products as well, or mode=reassign with target_category_id to move them to another category.
 // @Produce json
 // @Param Authorization header string true "Bearer token for authentication"
 // @Param id path int true "Category ID" Format(int64)
+// @Param mode query string false "Delete mode" Enums(restrict, cascade, reassign)
+// @Param target_category_id query int false "Category receiving the products when mode is reassign"
 // @Success 200 {object} SuccessResponse "Category deleted successfully"
-// @Failure 404 {object} ErrorResponse "Category not found"
-// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 404 {object} apperror.Problem "Category not found"
+// @Failure 409 {object} apperror.Problem "Category still has products"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
 // @Router /categories/{id} [delete]
 func DeleteCategory(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.Param("categoryId")
-		var existingCategory models.Category
-
-		if err := db.First(&existingCategory, id).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		categoryID, err := strconv.ParseUint(c.Param("categoryId"), 10, 0)
+		if err != nil {
+			apperror.Respond(c, apperror.NotFound("category_not_found", "Category not found"))
 			return
 		}
+		targetID, _ := strconv.ParseUint(c.Query("target_category_id"), 10, 0)
 
-		if err := db.Delete(&existingCategory, id).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		categoryService := services.CategoryService{Repository: repository.NewCategoryRepo(db)}
+		if err := categoryService.DeleteCategory(c.Request.Context(), uint(categoryID), services.DeleteCategoryInput{
+			Mode:             c.DefaultQuery("mode", services.DeleteModeRestrict),
+			TargetCategoryID: uint(targetID),
+		}); err != nil {
+			apperror.Respond(c, err)
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Category has been successfully deleted"})
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "category_deleted", nil)})
 	}
 }
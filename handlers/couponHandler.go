@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/i18n"
+	"e-commerce/models"
+	"e-commerce/services"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CouponInput is the request body for creating and updating coupons.
+type CouponInput struct {
+	Code           string     `json:"code"`
+	Type           string     `json:"type"`
+	Value          int        `json:"value"`
+	MinSpend       int        `json:"min_spend"`
+	StartsAt       *time.Time `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	MaxRedemptions int        `json:"max_redemptions"`
+	MaxPerUser     int        `json:"max_per_user"`
+	ProductID      *uint      `json:"product_id"`
+	CategoryID     *uint      `json:"category_id"`
+	Active         *bool      `json:"active"`
+}
+
+// CouponStats summarises how a coupon has been used.
+type CouponStats struct {
+	CouponID      uint   `json:"coupon_id"`
+	Code          string `json:"code"`
+	Redemptions   int64  `json:"redemptions"`
+	UniqueUsers   int64  `json:"unique_users"`
+	TotalDiscount int    `json:"total_discount"`
+	Revenue       int    `json:"revenue"`
+}
+
+// @Summary Create a coupon
+// @Description Create a discount coupon. Codes are case-insensitive and stored in upper case
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param coupon body CouponInput true "Coupon"
+// @Success 201 {object} models.Coupon "Coupon created"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Not Found"
+// @Failure 409 {object} apperror.Problem "Conflict"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /coupons [post]
+func CreateCoupon(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var userInput CouponInput
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+		newCoupon := models.Coupon{Active: true}
+		applyCouponInput(&newCoupon, userInput)
+		if err := validCouponInput(db, newCoupon); err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		var existingCoupon models.Coupon
+		if err := db.Unscoped().Where("code = ?", newCoupon.Code).First(&existingCoupon).Error; err == nil {
+			apperror.Respond(c, apperror.Conflict("coupon_code_exists", "Coupon code already exists!"))
+			return
+		}
+		if err := db.Create(&newCoupon).Error; err != nil {
+			apperror.Respond(c, apperror.Internal(err, "coupon_create_failed", "Failed to create coupon"))
+			return
+		}
+		c.JSON(http.StatusCreated, newCoupon)
+	}
+}
+
+// @Summary Get all coupons
+// @Tags Coupons
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} models.Coupon "List of coupons"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /coupons [get]
+func GetCoupons(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		coupons := []models.Coupon{}
+		if err := db.Order("id").Find(&coupons).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, coupons)
+	}
+}
+
+// @Summary Update a coupon
+// @Tags Coupons
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param couponId path integer true "Coupon ID"
+// @Param coupon body CouponInput true "Coupon"
+// @Success 200 {object} models.Coupon "Updated coupon"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Coupon not found"
+// @Failure 409 {object} apperror.Problem "Conflict"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /coupons/{couponId} [put]
+func UpdateCoupon(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingCoupon models.Coupon
+		if err := db.First(&existingCoupon, c.Param("couponId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("coupon_not_found", "Coupon not found"))
+			return
+		}
+		var userInput CouponInput
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+		applyCouponInput(&existingCoupon, userInput)
+		if err := validCouponInput(db, existingCoupon); err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		var duplicate models.Coupon
+		if err := db.Unscoped().Where("code = ? AND id <> ?", existingCoupon.Code, existingCoupon.ID).First(&duplicate).Error; err == nil {
+			apperror.Respond(c, apperror.Conflict("coupon_code_exists", "Coupon code already exists!"))
+			return
+		}
+		if err := db.Save(&existingCoupon).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, existingCoupon)
+	}
+}
+
+// @Summary Delete a coupon
+// @Tags Coupons
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param couponId path integer true "Coupon ID"
+// @Success 200 {object} SuccessResponse "Coupon deleted"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Coupon not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /coupons/{couponId} [delete]
+func DeleteCoupon(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingCoupon models.Coupon
+		if err := db.First(&existingCoupon, c.Param("couponId")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperror.Respond(c, apperror.NotFound("coupon_not_found", "Coupon not found"))
+				return
+			}
+			apperror.Respond(c, err)
+			return
+		}
+		if err := db.Delete(&existingCoupon).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "coupon_deleted", nil)})
+	}
+}
+
+// @Summary Coupon usage statistics
+// @Description Number of redemptions and unique users, the total discount given and the revenue of purchases made with the coupon
+// @Tags Coupons
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param couponId path integer true "Coupon ID"
+// @Success 200 {object} CouponStats "Coupon statistics"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Coupon not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /coupons/{couponId}/stats [get]
+func GetCouponStats(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingCoupon models.Coupon
+		if err := db.Unscoped().First(&existingCoupon, c.Param("couponId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("coupon_not_found", "Coupon not found"))
+			return
+		}
+		var stats CouponStats
+		if err := db.Model(&models.CouponRedemption{}).
+			Select("COUNT(*) AS redemptions, COUNT(DISTINCT user_id) AS unique_users, COALESCE(SUM(discount), 0) AS total_discount").
+			Where("coupon_id = ?", existingCoupon.ID).
+			Scan(&stats).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		if err := db.Model(&models.TransactionHistory{}).
+			Select("COALESCE(SUM(total_price), 0)").
+			Where("coupon_id = ?", existingCoupon.ID).
+			Scan(&stats.Revenue).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		stats.CouponID, stats.Code = existingCoupon.ID, existingCoupon.Code
+		c.JSON(http.StatusOK, stats)
+	}
+}
+
+func applyCouponInput(coupon *models.Coupon, input CouponInput) {
+	coupon.Code = services.NormalizeCouponCode(input.Code)
+	coupon.Type = input.Type
+	coupon.Value = input.Value
+	coupon.MinSpend = input.MinSpend
+	coupon.StartsAt = input.StartsAt
+	coupon.EndsAt = input.EndsAt
+	coupon.MaxRedemptions = input.MaxRedemptions
+	coupon.MaxPerUser = input.MaxPerUser
+	coupon.ProductID = input.ProductID
+	coupon.CategoryID = input.CategoryID
+	if input.Active != nil {
+		coupon.Active = *input.Active
+	}
+}
+
+func validCouponInput(db *gorm.DB, coupon models.Coupon) error {
+	if err := services.ValidateCoupon(couponEntity(coupon)); err != nil {
+		return err
+	}
+	if coupon.ProductID != nil {
+		if err := db.First(&models.Product{}, *coupon.ProductID).Error; err != nil {
+			return apperror.NotFound("product_not_found", "Product not found")
+		}
+	}
+	if coupon.CategoryID != nil {
+		if err := db.First(&models.Category{}, *coupon.CategoryID).Error; err != nil {
+			return apperror.NotFound("category_not_found", "Category not found")
+		}
+	}
+	return nil
+}
+
+func couponEntity(coupon models.Coupon) entity.Coupon {
+	return entity.Coupon{
+		ID:             coupon.ID,
+		Code:           coupon.Code,
+		Type:           coupon.Type,
+		Value:          coupon.Value,
+		MinSpend:       coupon.MinSpend,
+		StartsAt:       coupon.StartsAt,
+		EndsAt:         coupon.EndsAt,
+		MaxRedemptions: coupon.MaxRedemptions,
+		MaxPerUser:     coupon.MaxPerUser,
+		ProductID:      coupon.ProductID,
+		CategoryID:     coupon.CategoryID,
+		Active:         coupon.Active,
+	}
+}
+
+// redeemCoupon locks the coupon row, checks it against line using the
+// redemptions made so far and records a new redemption for userID.
+func redeemCoupon(tx *gorm.DB, couponID, userID uint, line services.PurchaseLine) (*models.CouponRedemption, error) {
+	var coupon models.Coupon
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&coupon, couponID).Error; err != nil {
+		return nil, err
+	}
+	usage, err := couponUsage(tx, coupon.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	discount, err := services.CalculateDiscount(couponEntity(coupon), line, usage, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	redemption := models.CouponRedemption{CouponID: coupon.ID, UserID: userID, Discount: discount}
+	if err := tx.Create(&redemption).Error; err != nil {
+		return nil, err
+	}
+	return &redemption, nil
+}
+
+func couponUsage(db *gorm.DB, couponID, userID uint) (services.CouponUsage, error) {
+	var usage services.CouponUsage
+	if err := db.Model(&models.CouponRedemption{}).Where("coupon_id = ?", couponID).Count(&usage.Total).Error; err != nil {
+		return usage, err
+	}
+	err := db.Model(&models.CouponRedemption{}).Where("coupon_id = ? AND user_id = ?", couponID, userID).Count(&usage.ByUser).Error
+	return usage, err
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/models"
+	"e-commerce/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errInsufficientStock = errors.New("insufficient stock")
+
+// @Summary Adjust product stock
+// @Description Record a stock movement for a product or one of its variants. Receipts and refunds add stock, damage removes it and adjustments take a signed quantity.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param type body string true "Movement type" Enums(receipt, refund, adjustment, damage)
+// @Param quantity body integer true "Quantity, signed for adjustments"
+// @Param variant_id body integer false "Variant ID"
+// @Param note body string false "Reason for the movement"
+// @Success 201 {object} models.InventoryMovement "Stock adjusted successfully"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/stock-adjustments [post]
+func CreateStockAdjustment(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+
+		var userInput struct {
+			Type      string `json:"type"`
+			Quantity  int    `json:"quantity"`
+			VariantID *uint  `json:"variant_id"`
+			Note      string `json:"note"`
+		}
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+		if userInput.Type == services.MovementSale {
+			apperror.Respond(c, apperror.Validation("sale_adjustment_not_allowed", "Sales are recorded by purchases"))
+			return
+		}
+		quantity, err := services.SignedQuantity(userInput.Type, userInput.Quantity)
+		if err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+		if userInput.VariantID != nil {
+			var existingVariant models.ProductVariant
+			if err := db.Where("id = ? AND product_id = ?", *userInput.VariantID, existingProduct.ID).First(&existingVariant).Error; err != nil {
+				apperror.Respond(c, apperror.NotFound("variant_not_found", "Variant not found"))
+				return
+			}
+		}
+
+		movement := models.InventoryMovement{
+			ProductID: existingProduct.ID,
+			VariantID: userInput.VariantID,
+			UserID:    actingUserID(c),
+			Type:      userInput.Type,
+			Quantity:  quantity,
+			Note:      userInput.Note,
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			return applyStockMovement(tx, &movement)
+		})
+		if errors.Is(err, errInsufficientStock) {
+			apperror.Respond(c, apperror.Validation("negative_stock", "Stock cannot go below zero"))
+			return
+		}
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, movement)
+	}
+}
+
+// @Summary Get product stock history
+// @Description Get the stock journal of a product, newest first. Pass variant_id to get the journal of a single variant.
+// @Tags Inventory
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param variant_id query integer false "Variant ID"
+// @Success 200 {array} models.InventoryMovement "Stock movements"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/stock-history [get]
+func GetStockHistory(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+
+		stock := existingProduct.Stock
+		query := db.Where("product_id = ?", existingProduct.ID)
+		if variantID := c.Query("variant_id"); variantID != "" {
+			var existingVariant models.ProductVariant
+			if err := db.Where("id = ? AND product_id = ?", variantID, existingProduct.ID).First(&existingVariant).Error; err != nil {
+				apperror.Respond(c, apperror.NotFound("variant_not_found", "Variant not found"))
+				return
+			}
+			stock = existingVariant.Stock
+			query = query.Where("variant_id = ?", existingVariant.ID)
+		} else {
+			query = query.Where("variant_id IS NULL")
+		}
+
+		var movements []models.InventoryMovement
+		if err := query.Order("id DESC").Find(&movements).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"product_id": existingProduct.ID,
+			"stock":      stock,
+			"movements":  movements,
+		})
+	}
+}
+
+// applyStockMovement changes the stock of the product or variant of movement
+// and journals it, so stock always equals the sum of its movements. It must
+// run inside a transaction.
+func applyStockMovement(tx *gorm.DB, movement *models.InventoryMovement) error {
+	previous, err := lockStock(tx, movement.ProductID, movement.VariantID)
+	if err != nil {
+		return err
+	}
+	stock := previous + movement.Quantity
+	if stock < 0 {
+		return errInsufficientStock
+	}
+
+	var target interface{} = &models.Product{}
+	targetID := movement.ProductID
+	if movement.VariantID != nil {
+		target, targetID = &models.ProductVariant{}, *movement.VariantID
+	}
+	if err := tx.Model(target).Where("id = ?", targetID).Update("stock", stock).Error; err != nil {
+		return err
+	}
+	movement.StockAfter = stock
+	if err := tx.Create(movement).Error; err != nil {
+		return err
+	}
+	return recordStockEvents(tx, movement.ProductID, movement.VariantID, previous, stock)
+}
+
+// recordStockEvents stores the low, out of and back in stock events caused
+// by a stock change so the dispatcher can deliver them after commit.
+func recordStockEvents(tx *gorm.DB, productID uint, variantID *uint, previous, current int) error {
+	var threshold int
+	if err := tx.Model(&models.Product{}).Select("reorder_threshold").Where("id = ?", productID).Scan(&threshold).Error; err != nil {
+		return err
+	}
+	for _, eventType := range services.StockEventsFor(previous, current, threshold) {
+		if err := tx.Create(&models.StockEvent{
+			ProductID: productID,
+			VariantID: variantID,
+			Type:      eventType,
+			Stock:     current,
+			Threshold: threshold,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockStock locks the product or variant row for the rest of the
+// transaction and returns its current stock.
+func lockStock(tx *gorm.DB, productID uint, variantID *uint) (int, error) {
+	locked := tx.Clauses(clause.Locking{Strength: "UPDATE"})
+	if variantID != nil {
+		var variant models.ProductVariant
+		if err := locked.Where("product_id = ?", productID).First(&variant, *variantID).Error; err != nil {
+			return 0, err
+		}
+		return variant.Stock, nil
+	}
+	var product models.Product
+	if err := locked.First(&product, productID).Error; err != nil {
+		return 0, err
+	}
+	return product.Stock, nil
+}
+
+func actingUserID(c *gin.Context) uint {
+	id, _ := c.Get("id")
+	userID, _ := id.(uint)
+	return userID
+}
+
+func isAdmin(c *gin.Context) bool {
+	role, _ := c.Get("role")
+	return role == "admin"
+}
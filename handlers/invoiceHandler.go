@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/invoice"
+	"e-commerce/models"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// @Summary Download a transaction invoice
+// @Description Render the invoice of a transaction as PDF, the default, or HTML. Customers can only download invoices of their own transactions
+// @Tags Transactions
+// @Produce application/pdf,text/html
+// @Param Authorization header string true "Bearer token"
+// @Param transactionId path integer true "Transaction ID"
+// @Param format query string false "Invoice format" Enums(pdf, html)
+// @Success 200 {file} file "Invoice"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 404 {object} apperror.Problem "Transaction not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /transactions/{transactionId}/invoice [get]
+func GetInvoice(db *gorm.DB, seller invoice.Party) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		format := c.DefaultQuery("format", "pdf")
+		if format != "pdf" && format != "html" {
+			apperror.Respond(c, apperror.Validation("invalid_format", "Format must be pdf or html").With("formats", "pdf, html"))
+			return
+		}
+
+		var transaction models.TransactionHistory
+		query := db.Preload("Product").Preload("Variant")
+		if !isAdmin(c) {
+			query = query.Where("user_id = ?", actingUserID(c))
+		}
+		if err := query.First(&transaction, c.Param("transactionId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("transaction_not_found", "Transaction not found"))
+			return
+		}
+
+		var issued *models.Invoice
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			issued, err = issueInvoice(tx, transaction.ID, time.Now())
+			return err
+		})
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		inv, err := buildInvoice(db, transaction, *issued, seller)
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+
+		c.Header("Content-Disposition", "inline; filename="+inv.Filename()+"."+format)
+		if format == "html" {
+			c.Header("Content-Type", "text/html; charset=utf-8")
+			err = invoice.RenderHTML(c.Writer, inv)
+		} else {
+			c.Header("Content-Type", "application/pdf")
+			err = invoice.RenderPDF(c.Writer, inv)
+		}
+		if err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+// issueInvoice returns the invoice of a transaction, numbering it with the
+// next number of the year when it has none yet.
+func issueInvoice(tx *gorm.DB, transactionID uint, at time.Time) (*models.Invoice, error) {
+	var existing models.Invoice
+	err := tx.Where("transaction_id = ?", transactionID).First(&existing).Error
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	sequence := models.InvoiceSequence{Year: at.Year()}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sequence).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sequence, "year = ?", at.Year()).Error; err != nil {
+		return nil, err
+	}
+	sequence.Last++
+	if err := tx.Model(&sequence).Update("last", sequence.Last).Error; err != nil {
+		return nil, err
+	}
+	issued := models.Invoice{
+		TransactionID: transactionID,
+		Number:        invoice.Number(sequence.Year, sequence.Last),
+		Year:          sequence.Year,
+		Sequence:      sequence.Last,
+		IssuedAt:      at,
+	}
+	if err := tx.Create(&issued).Error; err != nil {
+		return nil, err
+	}
+	return &issued, nil
+}
+
+func buildInvoice(db *gorm.DB, transaction models.TransactionHistory, issued models.Invoice, seller invoice.Party) (invoice.Invoice, error) {
+	var buyer models.User
+	if err := db.First(&buyer, transaction.UserID).Error; err != nil {
+		return invoice.Invoice{}, err
+	}
+	var couponCode string
+	if transaction.CouponID != nil {
+		var coupon models.Coupon
+		if err := db.Unscoped().First(&coupon, *transaction.CouponID).Error; err == nil {
+			couponCode = coupon.Code
+		}
+	}
+
+	line := invoice.Line{
+		Description: transaction.Product.Title,
+		SKU:         transaction.Product.SKU,
+		Quantity:    transaction.Quantity,
+		Amount:      transaction.Subtotal,
+	}
+	if transaction.Variant != nil {
+		line.SKU = transaction.Variant.SKU
+		options := strings.TrimSpace(transaction.Variant.Size + " " + transaction.Variant.Color)
+		if options != "" {
+			line.Description += " (" + options + ")"
+		}
+	}
+	if transaction.Quantity != 0 {
+		line.UnitPrice = transaction.Subtotal / transaction.Quantity
+	}
+
+	shipTo := transaction.ShippingAddress
+	var address []string
+	for _, part := range []string{shipTo.Street, strings.TrimSpace(shipTo.City + " " + shipTo.PostalCode), shipTo.Province} {
+		if part != "" {
+			address = append(address, part)
+		}
+	}
+	buyerName := buyer.FullName
+	if shipTo.RecipientName != "" && shipTo.RecipientName != buyer.FullName {
+		buyerName += " (attn. " + shipTo.RecipientName + ")"
+	}
+
+	return invoice.Invoice{
+		Number:      issued.Number,
+		IssuedAt:    issued.IssuedAt,
+		Seller:      seller,
+		Buyer:       invoice.Party{Name: buyerName, Email: buyer.Email, Phone: shipTo.Phone, Address: address},
+		Lines:       []invoice.Line{line},
+		Subtotal:    transaction.Subtotal,
+		Discount:    transaction.Discount,
+		CouponCode:  couponCode,
+		TaxName:     transaction.TaxName,
+		TaxRate:     transaction.TaxRate,
+		Tax:         transaction.Tax,
+		TaxIncluded: transaction.TaxIncluded,
+		Shipping:    transaction.ShippingCost,
+		Total:       transaction.TotalPrice,
+	}, nil
+}
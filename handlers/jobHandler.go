@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/i18n"
+	"e-commerce/models"
+	"e-commerce/services"
+	"e-commerce/validation"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// JobInput is the request body for creating and updating scheduled jobs.
+type JobInput struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Schedule   string   `json:"schedule"`
+	Recipients []string `json:"recipients"`
+	PeriodDays int      `json:"period_days"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+// @Summary Get scheduled jobs
+// @Tags Jobs
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} models.ScheduledJob "List of scheduled jobs"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /jobs [get]
+func GetJobs(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		jobs := []models.ScheduledJob{}
+		if err := db.Order("id").Find(&jobs).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, jobs)
+	}
+}
+
+// @Summary Create a scheduled job
+// @Description Create a job that runs whenever its cron schedule (five fields, in UTC) matches. sales_report exports the sales summary, daily revenue, top products and top categories; transactions_export exports every transaction. Both cover the period_days days before the run (default 1) and mail the CSV files to the recipients
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param job body JobInput true "Scheduled job"
+// @Success 201 {object} models.ScheduledJob "Job created"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 409 {object} apperror.Problem "Conflict"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /jobs [post]
+func CreateJob(db *gorm.DB, jobService services.JobService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var userInput JobInput
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+		newJob := models.ScheduledJob{Enabled: true}
+		if err := applyJobInput(&newJob, userInput, jobService); err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+		var existingJob models.ScheduledJob
+		if err := db.Unscoped().Where("name = ?", newJob.Name).First(&existingJob).Error; err == nil {
+			apperror.Respond(c, apperror.Conflict("job_name_exists", "Job name already exists!"))
+			return
+		}
+		if err := db.Create(&newJob).Error; err != nil {
+			apperror.Respond(c, apperror.Internal(err, "job_create_failed", "Failed to create job"))
+			return
+		}
+		c.JSON(http.StatusCreated, newJob)
+	}
+}
+
+// @Summary Update a scheduled job
+// @Tags Jobs
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param jobId path integer true "Job ID"
+// @Param job body JobInput true "Scheduled job"
+// @Success 200 {object} models.ScheduledJob "Updated job"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Job not found"
+// @Failure 409 {object} apperror.Problem "Conflict"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /jobs/{jobId} [put]
+func UpdateJob(db *gorm.DB, jobService services.JobService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingJob models.ScheduledJob
+		if err := db.First(&existingJob, c.Param("jobId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("job_not_found", "Job not found"))
+			return
+		}
+		var userInput JobInput
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+		if err := applyJobInput(&existingJob, userInput, jobService); err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+		var duplicate models.ScheduledJob
+		if err := db.Unscoped().Where("name = ? AND id <> ?", existingJob.Name, existingJob.ID).First(&duplicate).Error; err == nil {
+			apperror.Respond(c, apperror.Conflict("job_name_exists", "Job name already exists!"))
+			return
+		}
+		// Leave the lock columns to the instance running the job
+		if err := db.Omit("locked_by", "locked_until").Save(&existingJob).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, existingJob)
+	}
+}
+
+// @Summary Delete a scheduled job
+// @Tags Jobs
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param jobId path integer true "Job ID"
+// @Success 200 {object} SuccessResponse "Job deleted"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Job not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /jobs/{jobId} [delete]
+func DeleteJob(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingJob models.ScheduledJob
+		if err := db.First(&existingJob, c.Param("jobId")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperror.Respond(c, apperror.NotFound("job_not_found", "Job not found"))
+				return
+			}
+			apperror.Respond(c, err)
+			return
+		}
+		// Jobs have no history to keep, so free the name for reuse
+		if err := db.Unscoped().Delete(&existingJob).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "job_deleted", nil)})
+	}
+}
+
+// @Summary Run a scheduled job now
+// @Description Make the job due, so the scheduler runs it on its next tick. The regular schedule resumes after the run
+// @Tags Jobs
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param jobId path integer true "Job ID"
+// @Success 202 {object} models.ScheduledJob "Job queued"
+// @Failure 400 {object} apperror.Problem "Job is disabled"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Job not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /jobs/{jobId}/run [post]
+func RunJob(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingJob models.ScheduledJob
+		if err := db.First(&existingJob, c.Param("jobId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("job_not_found", "Job not found"))
+			return
+		}
+		if !existingJob.Enabled {
+			apperror.Respond(c, apperror.Validation("job_disabled", "Job is disabled"))
+			return
+		}
+		existingJob.NextRunAt = time.Now()
+		if err := db.Model(&existingJob).Update("next_run_at", existingJob.NextRunAt).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, existingJob)
+	}
+}
+
+// applyJobInput validates userInput and copies it onto job, scheduling its
+// next run.
+func applyJobInput(job *models.ScheduledJob, userInput JobInput, jobService services.JobService) error {
+	recipients := make([]string, 0, len(userInput.Recipients))
+	for _, recipient := range userInput.Recipients {
+		recipient = strings.TrimSpace(recipient)
+		if err := validation.Email(recipient); err != nil {
+			return err
+		}
+		recipients = append(recipients, recipient)
+	}
+	candidate := entity.ScheduledJob{
+		Name:       strings.TrimSpace(userInput.Name),
+		Type:       userInput.Type,
+		Schedule:   strings.TrimSpace(userInput.Schedule),
+		Recipients: recipients,
+		PeriodDays: userInput.PeriodDays,
+	}
+	if err := jobService.ValidateJob(candidate); err != nil {
+		return err
+	}
+	nextRunAt, err := services.NextJobRun(candidate.Schedule, time.Now())
+	if err != nil {
+		return err
+	}
+
+	job.Name = candidate.Name
+	job.Type = candidate.Type
+	job.Schedule = candidate.Schedule
+	job.Recipients = strings.Join(recipients, ",")
+	job.PeriodDays = candidate.PeriodDays
+	job.NextRunAt = nextRunAt
+	if userInput.Enabled != nil {
+		job.Enabled = *userInput.Enabled
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/i18n"
+	"e-commerce/models"
+	"e-commerce/services"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// @Summary Get a product's price history
+// @Description List every price the product has had, newest first, so past transactions can be matched with the price they were charged at
+// @Tags Products
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Success 200 {array} models.PriceHistory "Price history"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/price-history [get]
+func GetPriceHistory(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+		history := []models.PriceHistory{}
+		if err := db.Where("product_id = ?", existingProduct.ID).Order("created_at DESC, id DESC").Find(&history).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, history)
+	}
+}
+
+// @Summary Schedule a price change
+// @Description Change a product's price at starts_at. With ends_at the change is a sale and the previous price is restored at ends_at
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param price body integer true "New price"
+// @Param starts_at body string true "When the price takes effect (RFC 3339)"
+// @Param ends_at body string false "When the previous price is restored (RFC 3339)"
+// @Success 201 {object} models.ScheduledPriceChange "Price change scheduled"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 409 {object} apperror.Problem "Overlaps another scheduled change"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/price-schedules [post]
+func CreatePriceSchedule(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+		var userInput struct {
+			Price    int        `json:"price"`
+			StartsAt time.Time  `json:"starts_at"`
+			EndsAt   *time.Time `json:"ends_at"`
+		}
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+		change := entity.ScheduledPriceChange{
+			ProductID: existingProduct.ID,
+			Price:     userInput.Price,
+			StartsAt:  userInput.StartsAt,
+			EndsAt:    userInput.EndsAt,
+		}
+		if err := services.ValidateScheduledPriceChange(change, time.Now()); err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+
+		newSchedule := models.ScheduledPriceChange{
+			ProductID: existingProduct.ID,
+			UserID:    actingUserID(c),
+			Price:     userInput.Price,
+			StartsAt:  userInput.StartsAt,
+			EndsAt:    userInput.EndsAt,
+			Status:    entity.PriceChangePending,
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// Lock the product so two overlapping changes can't be scheduled at once
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Product{}, existingProduct.ID).Error; err != nil {
+				return err
+			}
+			var scheduled []models.ScheduledPriceChange
+			if err := tx.Where("product_id = ? AND status IN ?", existingProduct.ID, []string{entity.PriceChangePending, entity.PriceChangeActive}).
+				Find(&scheduled).Error; err != nil {
+				return err
+			}
+			for _, other := range scheduled {
+				if services.PriceChangesOverlap(change, entity.ScheduledPriceChange{StartsAt: other.StartsAt, EndsAt: other.EndsAt}) {
+					return errPriceChangeOverlaps
+				}
+			}
+			return tx.Create(&newSchedule).Error
+		})
+		if errors.Is(err, errPriceChangeOverlaps) {
+			apperror.Respond(c, apperror.Conflict("price_change_overlap", "Price change overlaps another scheduled change"))
+			return
+		}
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, newSchedule)
+	}
+}
+
+// @Summary Get a product's scheduled price changes
+// @Tags Products
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Success 200 {array} models.ScheduledPriceChange "Scheduled price changes"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/price-schedules [get]
+func GetPriceSchedules(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+		schedules := []models.ScheduledPriceChange{}
+		if err := db.Where("product_id = ?", existingProduct.ID).Order("starts_at").Find(&schedules).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, schedules)
+	}
+}
+
+// @Summary Cancel a scheduled price change
+// @Description Only changes that have not started yet can be cancelled
+// @Tags Products
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param scheduleId path integer true "Scheduled change ID"
+// @Success 200 {object} SuccessResponse "Price change cancelled"
+// @Failure 400 {object} apperror.Problem "Price change has already started"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Scheduled change not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/price-schedules/{scheduleId} [delete]
+func CancelPriceSchedule(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingSchedule models.ScheduledPriceChange
+		if err := db.Where("id = ? AND product_id = ?", c.Param("scheduleId"), c.Param("productId")).First(&existingSchedule).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("scheduled_change_not_found", "Scheduled change not found"))
+			return
+		}
+		result := db.Model(&existingSchedule).Where("status = ?", entity.PriceChangePending).Update("status", entity.PriceChangeCancelled)
+		if result.Error != nil {
+			apperror.Respond(c, result.Error)
+			return
+		}
+		if result.RowsAffected == 0 {
+			apperror.Respond(c, apperror.Validation("price_change_started", "Price change has already started"))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "price_change_cancelled", nil)})
+	}
+}
+
+// recordPriceChange adds an entry to the price history when a product's
+// price changes.
+func recordPriceChange(tx *gorm.DB, productID, userID uint, oldPrice, newPrice int, reason string) error {
+	if oldPrice == newPrice && reason != entity.PriceReasonInitial {
+		return nil
+	}
+	return tx.Create(&models.PriceHistory{
+		ProductID: productID,
+		UserID:    userID,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		Reason:    reason,
+	}).Error
+}
+
+var errPriceChangeOverlaps = errors.New("price change overlaps another scheduled change")
@@ -1,8 +1,14 @@
 package handlers
 
 import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
 	"e-commerce/helpers"
+	"e-commerce/i18n"
 	"e-commerce/models"
+	"e-commerce/money"
+	"e-commerce/services"
+	"e-commerce/validation"
 	"errors"
 	"net/http"
 
@@ -10,73 +16,94 @@ import (
 	"gorm.io/gorm"
 )
 
+// ProductInput is the request body for creating and updating products. The
+// SKU can only be set when the product is created.
+type ProductInput struct {
+	SKU              string `json:"sku"`
+	Title            string `json:"title" binding:"required"`
+	Price            int    `json:"price" binding:"price"`
+	Stock            int    `json:"stock" binding:"gte=0"`
+	CategoryID       int    `json:"category_id" binding:"required"`
+	ReorderThreshold int    `json:"reorder_threshold" binding:"gte=0"`
+	Weight           int    `json:"weight" binding:"gte=0"`
+}
+
 // @Summary Create a new product
 // @Description Create a new product with the provided details
 // @Tags Products
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer token"
+// @Param sku body string false "Product SKU"
 // @Param title body string true "Product title"
 // @Param price body integer true "Product price"
-// @Param stock body integer true "Product stock"
+// @Param stock body integer false "Product stock, defaults to 0"
 // @Param category_id body integer true "Category ID"
+// @Param reorder_threshold body integer false "Stock level at or below which a low-stock alert is sent, 0 disables alerts"
+// @Param weight body integer false "Shipping weight in grams"
 // @Success 201 {object} models.Product "Product created successfully"
-// @Failure 400 {object} ErrorResponse "Bad Request"
-// @Failure 401 {object} ErrorResponse "Unauthorized"
-// @Failure 403 {object} ErrorResponse "Forbidden"
-// @Failure 404 {object} ErrorResponse "Not Found"
-// @Failure 409 {object} ErrorResponse "Conflict"
-// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 409 {object} apperror.Problem "Conflict"
+// @Failure 422 {object} apperror.Problem "Unknown category"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
 // @Router /products [post]
 func CreateProduct(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var userInput struct {
-			Title      string `json:"title"`
-			Price      int    `json:"price"`
-			Stock      int    `json:"stock"`
-			CategoryID int    `json:"category_id"`
-		}
+		db := db.WithContext(c.Request.Context())
+		var userInput ProductInput
 		// Bind only the specified fields from the JSON request
 		if err := c.ShouldBindJSON(&userInput); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		if userInput.Title == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Title cannot be empty"})
-			return
-		}
-		if userInput.Price == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Price can't be empty or zero"})
-			return
-		}
-		// Custom validation for the 'Price' field
-		if err := helpers.ValidatePrice(userInput.Price); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		if userInput.Stock == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Stock can't be empty or zero"})
+			apperror.Respond(c, apperror.InvalidBody(err))
 			return
 		}
-		if userInput.CategoryID == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Category can't be empty or zero"})
+		if err := categoryReference(db, userInput.CategoryID); err != nil {
+			apperror.Respond(c, err)
 			return
 		}
 		var existingProduct models.Product
 		if err := db.Where("title = ?", userInput.Title).First(&existingProduct).Error; err == nil {
-			c.JSON(http.StatusConflict, gin.H{"error": "Title already exists!"})
+			apperror.Respond(c, apperror.Conflict("title_exists", "Title already exists!"))
 			return
 		}
+		if userInput.SKU != "" {
+			if err := db.Where("sku = ?", userInput.SKU).First(&existingProduct).Error; err == nil {
+				apperror.Respond(c, apperror.Conflict("sku_exists", "SKU already exists!"))
+				return
+			}
+		}
 		newProduct := models.Product{
-			Title:      userInput.Title,
-			Price:      userInput.Price,
-			Stock:      userInput.Stock,
-			CategoryID: uint(userInput.CategoryID),
+			SKU:              userInput.SKU,
+			Title:            userInput.Title,
+			Price:            userInput.Price,
+			CategoryID:       uint(userInput.CategoryID),
+			ReorderThreshold: userInput.ReorderThreshold,
+			Weight:           userInput.Weight,
 		}
-		if err := db.Create(&newProduct).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create product"})
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&newProduct).Error; err != nil {
+				return err
+			}
+			if err := recordPriceChange(tx, newProduct.ID, actingUserID(c), 0, newProduct.Price, entity.PriceReasonInitial); err != nil {
+				return err
+			}
+			if userInput.Stock == 0 {
+				return nil
+			}
+			return applyStockMovement(tx, &models.InventoryMovement{
+				ProductID: newProduct.ID,
+				UserID:    actingUserID(c),
+				Type:      services.MovementReceipt,
+				Quantity:  userInput.Stock,
+				Note:      "Initial stock",
+			})
+		})
+		if err != nil {
+			apperror.Respond(c, apperror.Internal(err, "product_create_failed", "Failed to create product"))
 			return
 		}
+		newProduct.Stock = userInput.Stock
 		c.JSON(http.StatusCreated, newProduct)
 	}
 }
@@ -87,14 +114,27 @@ func CreateProduct(db *gorm.DB) gin.HandlerFunc {
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer token"
+// @Param currency query string false "Currency to show display_price in, e.g. USD"
 // @Success 200 {array} models.Product "List of products"
-// @Failure 401 {object} ErrorResponse "Unauthorized"
-// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
 // @Router /products [get]
-func GetProducts(db *gorm.DB) gin.HandlerFunc {
+func GetProducts(db *gorm.DB, rates money.RateProvider) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
 		var products []models.Product
-		db.Preload("Products").Find(&products)
+		db.Preload("Variants").Preload("Prices").Find(&products)
+		if err := fillAvailability(db, products); err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		if currency := c.Query("currency"); currency != "" {
+			if err := fillDisplayPrices(products, currency, rates); err != nil {
+				apperror.Respond(c, apperror.Invalid(err))
+				return
+			}
+		}
 		if len(products) == 0 {
 			c.JSON(http.StatusOK, []string{})
 		} else {
@@ -112,79 +152,92 @@ func GetProducts(db *gorm.DB) gin.HandlerFunc {
 // @Param productId path integer true "Product ID"
 // @Param title body string true "Product title"
 // @Param price body integer true "Product price"
-// @Param stock body integer true "Product stock"
+// @Param stock body integer false "Product stock, defaults to 0"
 // @Param category_id body integer true "Category ID"
+// @Param reorder_threshold body integer false "Stock level at or below which a low-stock alert is sent, 0 disables alerts"
+// @Param weight body integer false "Shipping weight in grams"
 // @Success 200 {object} models.Product "Updated product"
-// @Failure 400 {object} ErrorResponse "Bad Request"
-// @Failure 401 {object} ErrorResponse "Unauthorized"
-// @Failure 403 {object} ErrorResponse "Forbidden"
-// @Failure 404 {object} ErrorResponse "Not Found"
-// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Not Found"
+// @Failure 422 {object} apperror.Problem "Unknown category"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
 // @Router /products/{productId} [put]
 func UpdateProduct(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
 		id := c.Param("productId")
 
 		var existingProduct models.Product
 		if err := db.Where("id = ?", id).First(&existingProduct).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
 			return
 		}
 
-		var userInput struct {
-			Title      string `json:"title"`
-			Price      int    `json:"price"`
-			Stock      int    `json:"stock"`
-			CategoryID int    `json:"category_id"`
-		}
+		var userInput ProductInput
 
 		// Bind only the specified fields from the JSON request
 		if err := c.ShouldBindJSON(&userInput); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		if userInput.Title == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Title cannot be empty"})
-			return
-		}
-		if userInput.Price == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Price can't be empty or zero"})
-			return
-		}
-		// Custom validation for the 'Price' field
-		if err := helpers.ValidatePrice(userInput.Price); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		if userInput.Stock == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Stock can't be empty or zero"})
+			apperror.Respond(c, apperror.InvalidBody(err))
 			return
 		}
-		if userInput.CategoryID == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Category can't be empty or zero"})
+		if err := categoryReference(db, userInput.CategoryID); err != nil {
+			apperror.Respond(c, err)
 			return
 		}
 
+		oldPrice := existingProduct.Price
 		existingProduct.Title = userInput.Title
 		existingProduct.Price = userInput.Price
-		existingProduct.Stock = userInput.Stock
 		existingProduct.CategoryID = uint(userInput.CategoryID)
-		// Save the changes to the database
-		if err := db.Save(&existingProduct).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		existingProduct.ReorderThreshold = userInput.ReorderThreshold
+		existingProduct.Weight = userInput.Weight
+		// Save the changes to the database, journaling any stock change
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Omit("stock").Save(&existingProduct).Error; err != nil {
+				return err
+			}
+			if err := recordPriceChange(tx, existingProduct.ID, actingUserID(c), oldPrice, existingProduct.Price, entity.PriceReasonManual); err != nil {
+				return err
+			}
+			// Diff against the locked stock, not the earlier read, so a
+			// concurrent purchase is not undone by this adjustment.
+			current, err := lockStock(tx, existingProduct.ID, nil)
+			if err != nil {
+				return err
+			}
+			if delta := userInput.Stock - current; delta != 0 {
+				return applyStockMovement(tx, &models.InventoryMovement{
+					ProductID: existingProduct.ID,
+					UserID:    actingUserID(c),
+					Type:      services.MovementAdjustment,
+					Quantity:  delta,
+					Note:      "Product update",
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			apperror.Respond(c, err)
 			return
 		}
+		existingProduct.Stock = userInput.Stock
 		type ProductResponse struct {
-			Title      string `json:"title"`
-			Price      string `json:"price"`
-			Stock      int    `json:"stock"`
-			CategoryID int    `json:"category_id"`
+			Title            string `json:"title"`
+			Price            string `json:"price"`
+			Stock            int    `json:"stock"`
+			CategoryID       int    `json:"category_id"`
+			ReorderThreshold int    `json:"reorder_threshold"`
+			Weight           int    `json:"weight"`
 		}
 		response := ProductResponse{
-			Title:      existingProduct.Title,
-			Price:      helpers.FormatRupiah(existingProduct.Price),
-			Stock:      existingProduct.Stock,
-			CategoryID: int(existingProduct.CategoryID),
+			Title:            existingProduct.Title,
+			Price:            helpers.FormatRupiahIn(existingProduct.Price, i18n.FromContext(c.Request.Context())),
+			Stock:            existingProduct.Stock,
+			CategoryID:       int(existingProduct.CategoryID),
+			ReorderThreshold: existingProduct.ReorderThreshold,
+			Weight:           existingProduct.Weight,
 		}
 		c.JSON(http.StatusOK, gin.H{"product": response})
 	}
@@ -198,28 +251,49 @@ func UpdateProduct(db *gorm.DB) gin.HandlerFunc {
 // @Param Authorization header string true "Bearer token"
 // @Param productId path integer true "Product ID"
 // @Success 200 {string} string "Product has been successfully deleted"
-// @Failure 404 {object} ErrorResponse "Product not found"
-// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
 // @Router /products/{productId} [delete]
 func DeleteProduct(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
 		id := c.Param("productId")
 		var existingProduct models.Product
 
 		if err := db.First(&existingProduct, id).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+				apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
 				return
 			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			apperror.Respond(c, err)
 			return
 		}
 
-		if err := db.Delete(&existingProduct, id).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		// Schedules of a deleted product can never be applied
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.ScheduledPriceChange{}).
+				Where("product_id = ? AND status IN ?", existingProduct.ID, []string{entity.PriceChangePending, entity.PriceChangeActive}).
+				Update("status", entity.PriceChangeCancelled).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&existingProduct).Error
+		}); err != nil {
+			apperror.Respond(c, err)
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Product has been successfully deleted"})
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "product_deleted", nil)})
+	}
+}
+
+// categoryReference checks the category_id of a request body. An unknown id
+// is a mistake in the body rather than a missing resource, so it is
+// unprocessable instead of not found.
+func categoryReference(db *gorm.DB, categoryID int) error {
+	err := db.First(&models.Category{}, categoryID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.Unprocessable("unknown_category", "category_id does not refer to an existing category",
+			validation.Field("category_id", "exists", nil))
 	}
+	return err
 }
@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"e-commerce/apperror"
+	"e-commerce/helpers"
+	"e-commerce/i18n"
+	"e-commerce/models"
+	"e-commerce/services"
+	"e-commerce/storage"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	maxImageSize  = 5 << 20
+	thumbnailSize = 256
+)
+
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// @Summary Upload a product image
+// @Description Upload a JPEG, PNG or GIF image (max 5 MB and the configured dimensions) to the end of the product gallery. A thumbnail is generated automatically.
+// @Tags Product Images
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param image formData file true "Image file"
+// @Success 201 {object} models.ProductImage "Image uploaded successfully"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 413 {object} apperror.Problem "Image too large"
+// @Failure 415 {object} apperror.Problem "Unsupported image type"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/images [post]
+func UploadProductImage(db *gorm.DB, store storage.BlobStore, signer storage.URLSigner, limits services.ImageLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImageSize+1<<20)
+		fileHeader, err := c.FormFile("image")
+		if err != nil {
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				apperror.Respond(c, apperror.New(apperror.ErrTooLarge, "image_too_large", "Image must not be larger than 5 MB"))
+				return
+			}
+			apperror.Respond(c, apperror.Validation("image_required", "Image file is required"))
+			return
+		}
+		if fileHeader.Size > maxImageSize {
+			apperror.Respond(c, apperror.New(apperror.ErrTooLarge, "image_too_large", "Image must not be larger than 5 MB"))
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(file, maxImageSize+1))
+		file.Close()
+		if err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+
+		contentType := http.DetectContentType(data)
+		extension, ok := imageExtensions[contentType]
+		if !ok {
+			apperror.Respond(c, apperror.New(apperror.ErrUnsupportedMedia, "unsupported_image_type", "Only JPEG, PNG and GIF images are allowed"))
+			return
+		}
+		// Check the dimensions from the header before decoding the pixels
+		dimensions, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			apperror.Respond(c, apperror.Validation("invalid_image", "Image could not be decoded"))
+			return
+		}
+		if err := limits.Check(dimensions.Width, dimensions.Height); err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			apperror.Respond(c, apperror.Validation("invalid_image", "Image could not be decoded"))
+			return
+		}
+
+		var thumbnail bytes.Buffer
+		thumbnailType := "image/png"
+		thumbnailExtension := ".png"
+		if contentType == "image/jpeg" {
+			thumbnailType, thumbnailExtension = contentType, extension
+			err = jpeg.Encode(&thumbnail, helpers.Thumbnail(img, thumbnailSize), &jpeg.Options{Quality: 85})
+		} else {
+			err = png.Encode(&thumbnail, helpers.Thumbnail(img, thumbnailSize))
+		}
+		if err != nil {
+			apperror.Respond(c, apperror.Internal(err, "thumbnail_failed", "Failed to create thumbnail"))
+			return
+		}
+
+		name, err := randomName()
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		key := fmt.Sprintf("products/%d/%s%s", existingProduct.ID, name, extension)
+		thumbnailKey := fmt.Sprintf("products/%d/thumbnails/%s%s", existingProduct.ID, name, thumbnailExtension)
+
+		if err := store.Put(key, bytes.NewReader(data), contentType); err != nil {
+			apperror.Respond(c, apperror.Internal(err, "image_store_failed", "Failed to store image"))
+			return
+		}
+		if err := store.Put(thumbnailKey, &thumbnail, thumbnailType); err != nil {
+			store.Delete(key)
+			apperror.Respond(c, apperror.Internal(err, "thumbnail_store_failed", "Failed to store thumbnail"))
+			return
+		}
+
+		newImage := models.ProductImage{
+			ProductID:    existingProduct.ID,
+			Key:          key,
+			ThumbnailKey: thumbnailKey,
+			ContentType:  contentType,
+			Size:         int64(len(data)),
+			Width:        img.Bounds().Dx(),
+			Height:       img.Bounds().Dy(),
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			var lastPosition int
+			if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", existingProduct.ID).
+				Select("COALESCE(MAX(position), 0)").Scan(&lastPosition).Error; err != nil {
+				return err
+			}
+			newImage.Position = lastPosition + 1
+			return tx.Create(&newImage).Error
+		})
+		if err != nil {
+			store.Delete(key)
+			store.Delete(thumbnailKey)
+			apperror.Respond(c, apperror.Internal(err, "image_save_failed", "Failed to save image"))
+			return
+		}
+
+		signImageURLs(&newImage, signer)
+		c.JSON(http.StatusCreated, newImage)
+	}
+}
+
+// @Summary Get product images
+// @Description Get the product gallery in display order with signed URLs
+// @Tags Product Images
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Success 200 {array} models.ProductImage "Product gallery"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/images [get]
+func GetProductImages(db *gorm.DB, signer storage.URLSigner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+
+		var images []models.ProductImage
+		if err := db.Where("product_id = ?", existingProduct.ID).Order("position").Find(&images).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		for i := range images {
+			signImageURLs(&images[i], signer)
+		}
+		c.JSON(http.StatusOK, images)
+	}
+}
+
+// @Summary Reorder product images
+// @Description Set the gallery order. image_ids must contain every image of the product exactly once.
+// @Tags Product Images
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param image_ids body []int true "Image IDs in display order"
+// @Success 200 {object} SuccessResponse "Gallery order updated"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/images/order [put]
+func ReorderProductImages(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+
+		var userInput struct {
+			ImageIDs []uint `json:"image_ids"`
+		}
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+
+		var images []models.ProductImage
+		if err := db.Where("product_id = ?", existingProduct.ID).Find(&images).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		remaining := make(map[uint]bool, len(images))
+		for _, img := range images {
+			remaining[img.ID] = true
+		}
+		for _, id := range userInput.ImageIDs {
+			if !remaining[id] {
+				apperror.Respond(c, apperror.Validation("invalid_image_order", "image_ids must list every image of the product exactly once"))
+				return
+			}
+			delete(remaining, id)
+		}
+		if len(remaining) > 0 {
+			apperror.Respond(c, apperror.Validation("invalid_image_order", "image_ids must list every image of the product exactly once"))
+			return
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for position, id := range userInput.ImageIDs {
+				if err := tx.Model(&models.ProductImage{}).Where("id = ?", id).Update("position", position+1).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "gallery_reordered", nil)})
+	}
+}
+
+// @Summary Delete a product image
+// @Tags Product Images
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param imageId path integer true "Image ID"
+// @Success 200 {object} SuccessResponse "Image has been successfully deleted"
+// @Failure 404 {object} apperror.Problem "Image not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/images/{imageId} [delete]
+func DeleteProductImage(db *gorm.DB, store storage.BlobStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingImage models.ProductImage
+		if err := db.Where("id = ? AND product_id = ?", c.Param("imageId"), c.Param("productId")).First(&existingImage).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperror.Respond(c, apperror.NotFound("image_not_found", "Image not found"))
+				return
+			}
+			apperror.Respond(c, err)
+			return
+		}
+
+		if err := db.Unscoped().Delete(&existingImage).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		if err := store.Delete(existingImage.Key); err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		if err := store.Delete(existingImage.ThumbnailKey); err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "image_deleted", nil)})
+	}
+}
+
+// @Summary Serve an image
+// @Description Serve a stored image. The URL must carry a valid signature from the gallery endpoints.
+// @Tags Product Images
+// @Produce image/jpeg,image/png,image/gif
+// @Param key path string true "Image key"
+// @Param expires query int true "Expiry as unix time"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file "Image"
+// @Failure 403 {object} apperror.Problem "Invalid or expired signature"
+// @Failure 404 {object} apperror.Problem "Image not found"
+// @Router /images/{key} [get]
+func ServeImage(store storage.BlobStore, signer storage.URLSigner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimPrefix(c.Param("key"), "/")
+		if err := signer.Verify(key, c.Query("expires"), c.Query("signature"), time.Now()); err != nil {
+			apperror.Respond(c, apperror.Forbidden("invalid_signature", "Invalid or expired signature"))
+			return
+		}
+
+		body, contentType, err := store.Get(key)
+		if errors.Is(err, storage.ErrNotFound) {
+			apperror.Respond(c, apperror.NotFound("image_not_found", "Image not found"))
+			return
+		}
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		defer body.Close()
+
+		c.DataFromReader(http.StatusOK, -1, contentType, body, map[string]string{
+			"Cache-Control": "private, max-age=600",
+		})
+	}
+}
+
+func signImageURLs(img *models.ProductImage, signer storage.URLSigner) {
+	now := time.Now()
+	img.URL = signer.SignedURL(img.Key, now)
+	img.ThumbnailURL = signer.SignedURL(img.ThumbnailKey, now)
+}
+
+func randomName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
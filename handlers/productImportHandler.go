@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/logging"
+	"e-commerce/models"
+	"e-commerce/services"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ImportResult summarises a product import.
+type ImportResult struct {
+	DryRun            bool                   `json:"dry_run"`
+	Created           int                    `json:"created"`
+	Updated           int                    `json:"updated"`
+	CategoriesCreated []string               `json:"categories_created"`
+	Errors            []services.ImportError `json:"errors"`
+}
+
+var errDryRun = errors.New("dry run")
+
+// @Summary Import products
+// @Description Create or update products from a CSV or JSON file. Rows are matched by sku, then by title. Missing categories are created by type. Invalid rows and rows the database rejects are skipped and reported; with dry_run=true nothing is saved.
+// @Tags Products
+// @Accept multipart/form-data,text/csv,application/json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param file formData file false "CSV or JSON file, the request body is used when empty"
+// @Param format query string false "File format, detected from the file name or content type when empty" Enums(csv, json)
+// @Param dry_run query bool false "Validate and report without saving"
+// @Success 200 {object} ImportResult "Import result"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/import [post]
+func ImportProducts(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+		format := c.Query("format")
+
+		var body io.Reader = c.Request.Body
+		if fileHeader, err := c.FormFile("file"); err == nil {
+			file, err := fileHeader.Open()
+			if err != nil {
+				apperror.Respond(c, apperror.Invalid(err))
+				return
+			}
+			defer file.Close()
+			body = file
+			if format == "" {
+				format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+			}
+		}
+		if format == "" {
+			format = "csv"
+			if strings.Contains(c.ContentType(), "json") {
+				format = "json"
+			}
+		}
+
+		rows, err := services.ParseProductImport(format, body)
+		if err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+
+		result := ImportResult{DryRun: dryRun, CategoriesCreated: []string{}, Errors: []services.ImportError{}}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			categories := map[string]uint{}
+			for _, row := range rows {
+				if errs := row.Validate(); len(errs) > 0 {
+					result.Errors = append(result.Errors, errs...)
+					continue
+				}
+
+				// Each row runs in a savepoint, so a row the database rejects
+				// is rolled back and reported without aborting the import
+				rowResult := ImportResult{}
+				categoryID, cached := categories[row.Category]
+				categoryCreated := false
+				err := tx.Transaction(func(tx *gorm.DB) error {
+					if !cached {
+						var category models.Category
+						err := tx.Where("type = ?", row.Category).First(&category).Error
+						if errors.Is(err, gorm.ErrRecordNotFound) {
+							category = models.Category{Type: row.Category}
+							if err := tx.Create(&category).Error; err != nil {
+								return err
+							}
+							categoryCreated = true
+						} else if err != nil {
+							return err
+						}
+						categoryID = category.ID
+					}
+					return importProductRow(tx, row, categoryID, actingUserID(c), &rowResult)
+				})
+				if err != nil {
+					if c.Request.Context().Err() != nil {
+						return err
+					}
+					logging.Request(c).Warn("importing product row failed", "row", row.Row, "error", err)
+					result.Errors = append(result.Errors, services.ImportError{Row: row.Row, Message: "could not be saved"})
+					continue
+				}
+				if !cached {
+					categories[row.Category] = categoryID
+				}
+				if categoryCreated {
+					result.CategoriesCreated = append(result.CategoriesCreated, row.Category)
+				}
+				result.Created += rowResult.Created
+				result.Updated += rowResult.Updated
+				result.Errors = append(result.Errors, rowResult.Errors...)
+			}
+			if dryRun {
+				return errDryRun
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errDryRun) {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+func importProductRow(tx *gorm.DB, row services.ProductImportRow, categoryID uint, userID uint, result *ImportResult) error {
+	var existingProduct models.Product
+	err := gorm.ErrRecordNotFound
+	if row.SKU != "" {
+		err = tx.Where("sku = ?", row.SKU).First(&existingProduct).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = tx.Where("title = ?", row.Title).First(&existingProduct).Error
+		if err == nil && row.SKU != "" && existingProduct.SKU != "" {
+			result.Errors = append(result.Errors, services.ImportError{Row: row.Row, Field: "sku", Message: "does not match the product with this title"})
+			return nil
+		}
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		newProduct := models.Product{
+			SKU:              row.SKU,
+			Title:            row.Title,
+			Price:            row.Price,
+			CategoryID:       categoryID,
+			ReorderThreshold: row.ReorderThreshold,
+			Weight:           row.Weight,
+		}
+		if err := tx.Create(&newProduct).Error; err != nil {
+			return err
+		}
+		if err := recordPriceChange(tx, newProduct.ID, userID, 0, newProduct.Price, entity.PriceReasonInitial); err != nil {
+			return err
+		}
+		result.Created++
+		if row.Stock == 0 {
+			return nil
+		}
+		return applyStockMovement(tx, &models.InventoryMovement{
+			ProductID: newProduct.ID,
+			UserID:    userID,
+			Type:      services.MovementReceipt,
+			Quantity:  row.Stock,
+			Note:      "Import",
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if row.SKU != "" {
+		existingProduct.SKU = row.SKU
+	}
+	oldPrice := existingProduct.Price
+	existingProduct.Title = row.Title
+	existingProduct.Price = row.Price
+	existingProduct.CategoryID = categoryID
+	existingProduct.ReorderThreshold = row.ReorderThreshold
+	existingProduct.Weight = row.Weight
+	if err := tx.Omit("stock").Save(&existingProduct).Error; err != nil {
+		return err
+	}
+	if err := recordPriceChange(tx, existingProduct.ID, userID, oldPrice, existingProduct.Price, entity.PriceReasonManual); err != nil {
+		return err
+	}
+	result.Updated++
+	if delta := row.Stock - existingProduct.Stock; delta != 0 {
+		return applyStockMovement(tx, &models.InventoryMovement{
+			ProductID: existingProduct.ID,
+			UserID:    userID,
+			Type:      services.MovementAdjustment,
+			Quantity:  delta,
+			Note:      "Import",
+		})
+	}
+	return nil
+}
+
+// @Summary Export products
+// @Description Stream every product as CSV or JSON in the import format
+// @Tags Products
+// @Produce text/csv,application/json
+// @Param Authorization header string true "Bearer token"
+// @Param format query string false "Export format" Enums(csv, json)
+// @Success 200 {file} file "Product export"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/export [get]
+func ExportProducts(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		format := c.DefaultQuery("format", "csv")
+		if format != "csv" && format != "json" {
+			apperror.Respond(c, apperror.Validation("invalid_format", "Format must be csv or json").With("formats", "csv, json"))
+			return
+		}
+
+		rows, err := db.Model(&models.Product{}).
+			Select("products.sku, products.title, products.price, products.stock, categories.type AS category, products.reorder_threshold, products.weight").
+			Joins("LEFT JOIN categories ON categories.id = products.category_id").
+			Order("products.id").Rows()
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		defer rows.Close()
+
+		c.Header("Content-Disposition", "attachment; filename=products."+format)
+		if format == "csv" {
+			c.Header("Content-Type", "text/csv")
+			writer := csv.NewWriter(c.Writer)
+			writer.Write(services.ProductImportColumns)
+			for count := 1; rows.Next(); count++ {
+				var row services.ProductImportRow
+				if err := db.ScanRows(rows, &row); err != nil {
+					c.Error(err)
+					return
+				}
+				writer.Write(row.Record())
+				if count%100 == 0 {
+					writer.Flush()
+					c.Writer.Flush()
+				}
+			}
+			writer.Flush()
+			return
+		}
+
+		c.Header("Content-Type", "application/json")
+		c.Writer.WriteString("[")
+		encoder := json.NewEncoder(c.Writer)
+		for count := 0; rows.Next(); count++ {
+			var row services.ProductImportRow
+			if err := db.ScanRows(rows, &row); err != nil {
+				c.Error(err)
+				return
+			}
+			if count > 0 {
+				c.Writer.WriteString(",")
+			}
+			encoder.Encode(row)
+			if count%100 == 99 {
+				c.Writer.Flush()
+			}
+		}
+		c.Writer.WriteString("]")
+	}
+}
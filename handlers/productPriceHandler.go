@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/models"
+	"e-commerce/money"
+	"e-commerce/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// @Summary Set a product's foreign currency prices
+// @Description Replace the product's prices in currencies other than rupiah. Amounts are in the currency's minor units, so 1099 USD is $10.99. Currencies without a price are converted from the rupiah price
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param prices body []money.Money true "Prices"
+// @Success 200 {array} models.ProductPrice "Product prices"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/prices [put]
+func SetProductPrices(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+		var userInput []money.Money
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+		prices := make([]models.ProductPrice, 0, len(userInput))
+		seen := map[string]bool{}
+		for _, input := range userInput {
+			price := money.New(input.Amount, input.Currency)
+			if err := services.ValidateProductPrice(price); err != nil {
+				apperror.Respond(c, apperror.Invalid(err))
+				return
+			}
+			if seen[price.Currency] {
+				apperror.Respond(c, apperror.Validation("duplicate_currency", "Currency "+price.Currency+" is listed more than once").With("currency", price.Currency))
+				return
+			}
+			seen[price.Currency] = true
+			prices = append(prices, models.ProductPrice{ProductID: existingProduct.ID, Currency: price.Currency, Amount: price.Amount})
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Where("product_id = ?", existingProduct.ID).Delete(&models.ProductPrice{}).Error; err != nil {
+				return err
+			}
+			if len(prices) == 0 {
+				return nil
+			}
+			return tx.Create(&prices).Error
+		})
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, prices)
+	}
+}
+
+// fillDisplayPrices sets DisplayPrice on products and their variants to
+// their price in currency. Products need Prices and Variants preloaded.
+func fillDisplayPrices(products []models.Product, currency string, rates money.RateProvider) error {
+	for i := range products {
+		product := &products[i]
+		prices := make([]entity.ProductPrice, 0, len(product.Prices))
+		for _, price := range product.Prices {
+			prices = append(prices, entity.ProductPrice{ProductID: price.ProductID, Price: price.Money()})
+		}
+		displayPrice, err := services.PriceIn(product.Price, prices, currency, rates)
+		if err != nil {
+			return err
+		}
+		product.DisplayPrice = &displayPrice
+		for j := range product.Variants {
+			variant := &product.Variants[j]
+			variantPrice := displayPrice
+			if variant.Price != nil {
+				if variantPrice, err = services.PriceIn(*variant.Price, nil, currency, rates); err != nil {
+					return err
+				}
+			}
+			variant.DisplayPrice = &variantPrice
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/repository"
+	"e-commerce/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Report is the JSON form of a sales report. To is exclusive.
+type Report struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	Rows any       `json:"rows"`
+}
+
+type RevenueReportRow struct {
+	Period  string `json:"period"`
+	Orders  int64  `json:"orders"`
+	Units   int64  `json:"units"`
+	Revenue int64  `json:"revenue"`
+}
+
+type RankedReportRow struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Orders  int64  `json:"orders"`
+	Units   int64  `json:"units"`
+	Revenue int64  `json:"revenue"`
+}
+
+type SalesSummaryReport struct {
+	Orders            int64 `json:"orders"`
+	Units             int64 `json:"units"`
+	Revenue           int64 `json:"revenue"`
+	Discount          int64 `json:"discount"`
+	Tax               int64 `json:"tax"`
+	Shipping          int64 `json:"shipping"`
+	AverageOrderValue int64 `json:"average_order_value"`
+}
+
+// @Summary Revenue report
+// @Description Orders, units sold and revenue per UTC day, week (starting Monday) or month, including periods without sales. Covers the last 30 days unless from or to is given
+// @Tags Reports
+// @Produce json,text/csv
+// @Param Authorization header string true "Bearer token"
+// @Param from query string false "Start date, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "End date, RFC 3339 or YYYY-MM-DD (inclusive)"
+// @Param granularity query string false "Period length" Enums(day, week, month)
+// @Param format query string false "Output format" Enums(json, csv)
+// @Success 200 {object} Report{rows=[]RevenueReportRow} "Revenue report"
+// @Failure 400 {object} apperror.Problem "Bad request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /reports/revenue [get]
+func GetRevenueReport(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reportService, r, ok := reportRequest(c, db)
+		if !ok {
+			return
+		}
+		points, err := reportService.RevenueReport(c.Request.Context(), r, c.Query("granularity"))
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+
+		rows := make([]RevenueReportRow, 0, len(points))
+		for _, point := range points {
+			rows = append(rows, RevenueReportRow{Period: point.Period.Format(time.DateOnly), Orders: point.Orders, Units: point.Units, Revenue: point.Revenue})
+		}
+		writeReport(c, "revenue", r, rows, services.RevenueRecords(points))
+	}
+}
+
+// @Summary Top products report
+// @Description Best selling products by revenue or units sold. Covers the last 30 days unless from or to is given
+// @Tags Reports
+// @Produce json,text/csv
+// @Param Authorization header string true "Bearer token"
+// @Param from query string false "Start date, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "End date, RFC 3339 or YYYY-MM-DD (inclusive)"
+// @Param by query string false "Ranking" Enums(revenue, units)
+// @Param limit query integer false "Number of products, at most 100"
+// @Param format query string false "Output format" Enums(json, csv)
+// @Success 200 {object} Report{rows=[]RankedReportRow} "Top products"
+// @Failure 400 {object} apperror.Problem "Bad request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /reports/top-products [get]
+func GetTopProductsReport(db *gorm.DB) gin.HandlerFunc {
+	return rankedReport(db, "top-products", services.ReportService.TopProducts)
+}
+
+// @Summary Top categories report
+// @Description Best selling categories by revenue or units sold. Covers the last 30 days unless from or to is given
+// @Tags Reports
+// @Produce json,text/csv
+// @Param Authorization header string true "Bearer token"
+// @Param from query string false "Start date, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "End date, RFC 3339 or YYYY-MM-DD (inclusive)"
+// @Param by query string false "Ranking" Enums(revenue, units)
+// @Param limit query integer false "Number of categories, at most 100"
+// @Param format query string false "Output format" Enums(json, csv)
+// @Success 200 {object} Report{rows=[]RankedReportRow} "Top categories"
+// @Failure 400 {object} apperror.Problem "Bad request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /reports/top-categories [get]
+func GetTopCategoriesReport(db *gorm.DB) gin.HandlerFunc {
+	return rankedReport(db, "top-categories", services.ReportService.TopCategories)
+}
+
+// @Summary Top customers report
+// @Description Customers who spent the most or bought the most units. Covers the last 30 days unless from or to is given
+// @Tags Reports
+// @Produce json,text/csv
+// @Param Authorization header string true "Bearer token"
+// @Param from query string false "Start date, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "End date, RFC 3339 or YYYY-MM-DD (inclusive)"
+// @Param by query string false "Ranking" Enums(revenue, units)
+// @Param limit query integer false "Number of customers, at most 100"
+// @Param format query string false "Output format" Enums(json, csv)
+// @Success 200 {object} Report{rows=[]RankedReportRow} "Top customers"
+// @Failure 400 {object} apperror.Problem "Bad request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /reports/top-customers [get]
+func GetTopCustomersReport(db *gorm.DB) gin.HandlerFunc {
+	return rankedReport(db, "top-customers", services.ReportService.TopCustomers)
+}
+
+// @Summary Sales summary report
+// @Description Order count, units sold, revenue, discounts, tax, shipping and average order value. Covers the last 30 days unless from or to is given
+// @Tags Reports
+// @Produce json,text/csv
+// @Param Authorization header string true "Bearer token"
+// @Param from query string false "Start date, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "End date, RFC 3339 or YYYY-MM-DD (inclusive)"
+// @Param format query string false "Output format" Enums(json, csv)
+// @Success 200 {object} Report{rows=SalesSummaryReport} "Sales summary"
+// @Failure 400 {object} apperror.Problem "Bad request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /reports/summary [get]
+func GetSalesSummaryReport(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reportService, r, ok := reportRequest(c, db)
+		if !ok {
+			return
+		}
+		summary, err := reportService.Summary(c.Request.Context(), r)
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+
+		writeReport(c, "summary", r, SalesSummaryReport(*summary), services.SummaryRecords(*summary))
+	}
+}
+
+func rankedReport(db *gorm.DB, name string, top func(services.ReportService, context.Context, entity.ReportRange, string, int) ([]entity.RankedItem, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reportService, r, ok := reportRequest(c, db)
+		if !ok {
+			return
+		}
+		limit, err := queryInt(c, "limit")
+		if err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+		if limit == nil {
+			limit = new(int)
+		}
+		items, err := top(reportService, c.Request.Context(), r, c.Query("by"), *limit)
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+
+		rows := make([]RankedReportRow, 0, len(items))
+		for _, item := range items {
+			rows = append(rows, RankedReportRow(item))
+		}
+		writeReport(c, name, r, rows, services.RankedRecords(items))
+	}
+}
+
+// reportRequest reads the report range and checks the output format. It
+// writes the error response and returns false when either is invalid.
+func reportRequest(c *gin.Context, db *gorm.DB) (services.ReportService, entity.ReportRange, bool) {
+	reportService := services.ReportService{ReportRepository: repository.NewReportRepo(db)}
+	if format := c.DefaultQuery("format", "json"); format != "json" && format != "csv" {
+		apperror.Respond(c, apperror.Validation("invalid_format", "Format must be json or csv").With("formats", "json, csv"))
+		return reportService, entity.ReportRange{}, false
+	}
+	from, to, err := queryDateRange(c)
+	if err != nil {
+		apperror.Respond(c, apperror.Invalid(err))
+		return reportService, entity.ReportRange{}, false
+	}
+	r, err := reportService.ReportRange(from, to)
+	if err != nil {
+		apperror.Respond(c, err)
+		return reportService, r, false
+	}
+	return reportService, r, true
+}
+
+// writeReport writes rows as JSON, or records as CSV when format=csv.
+func writeReport(c *gin.Context, name string, r entity.ReportRange, rows any, records [][]string) {
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, Report{From: r.From, To: r.To, Rows: rows})
+		return
+	}
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", "attachment; filename="+name+".csv")
+	c.Writer.Write(services.CSV(records))
+}
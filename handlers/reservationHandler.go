@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/i18n"
+	"e-commerce/metrics"
+	"e-commerce/models"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// @Summary Reserve stock for checkout
+// @Description Hold stock of a product or variant for the authenticated user. The reservation expires automatically after the configured TTL.
+// @Tags Reservations
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param product_id body int true "Product ID"
+// @Param variant_id body int false "Variant ID, required when the product has variants"
+// @Param quantity body int true "Quantity to reserve"
+// @Success 201 {object} models.StockReservation "Stock reserved"
+// @Failure 400 {object} apperror.Problem "Bad request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 409 {object} apperror.Problem "Insufficient stock"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /reservations [post]
+func CreateReservation(db *gorm.DB, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var userInput struct {
+			ProductID uint  `json:"product_id"`
+			VariantID *uint `json:"variant_id"`
+			Quantity  int   `json:"quantity"`
+		}
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+		if userInput.Quantity <= 0 {
+			apperror.Respond(c, apperror.Validation("invalid_quantity", "Quantity must be greater than zero"))
+			return
+		}
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, userInput.ProductID).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+		if userInput.VariantID == nil {
+			var variantCount int64
+			if err := db.Model(&models.ProductVariant{}).Where("product_id = ?", existingProduct.ID).Count(&variantCount).Error; err != nil {
+				apperror.Respond(c, err)
+				return
+			}
+			if variantCount > 0 {
+				apperror.Respond(c, apperror.Validation("variant_required", "This product has variants, variant_id is required"))
+				return
+			}
+		}
+
+		reservation := models.StockReservation{
+			UserID:    actingUserID(c),
+			ProductID: existingProduct.ID,
+			VariantID: userInput.VariantID,
+			Quantity:  userInput.Quantity,
+			Status:    entity.ReservationActive,
+			ExpiresAt: time.Now().Add(ttl),
+		}
+		var available int
+		err := db.Transaction(func(tx *gorm.DB) error {
+			stock, err := lockStock(tx, reservation.ProductID, reservation.VariantID)
+			if err != nil {
+				return err
+			}
+			reserved, err := reservedQuantity(tx, reservation.ProductID, reservation.VariantID, 0)
+			if err != nil {
+				return err
+			}
+			available = max(stock-reserved, 0)
+			if reservation.Quantity > available {
+				return errInsufficientStock
+			}
+			return tx.Create(&reservation).Error
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			apperror.Respond(c, apperror.NotFound("variant_not_found", "Variant not found"))
+			return
+		}
+		if errors.Is(err, errInsufficientStock) {
+			metrics.OutOfStockRejections.WithLabelValues("reservation").Inc()
+			apperror.Respond(c, apperror.InsufficientStock(available))
+			return
+		}
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, reservation)
+	}
+}
+
+// @Summary Get my reservations
+// @Description Get the active stock reservations of the authenticated user
+// @Tags Reservations
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} models.StockReservation "Active reservations"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /reservations [get]
+func GetMyReservations(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var reservations []models.StockReservation
+		if err := db.Where("user_id = ? AND status = ? AND expires_at > ?", actingUserID(c), entity.ReservationActive, time.Now()).
+			Order("expires_at").Find(&reservations).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, reservations)
+	}
+}
+
+// @Summary Release a reservation
+// @Description Give reserved stock back before the reservation expires
+// @Tags Reservations
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param reservationId path int true "Reservation ID"
+// @Success 200 {object} SuccessResponse "Reservation released"
+// @Failure 400 {object} apperror.Problem "Reservation is no longer active"
+// @Failure 404 {object} apperror.Problem "Reservation not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /reservations/{reservationId} [delete]
+func ReleaseReservation(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var reservation models.StockReservation
+		if err := db.Where("id = ? AND user_id = ?", c.Param("reservationId"), actingUserID(c)).First(&reservation).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("reservation_not_found", "Reservation not found"))
+			return
+		}
+		if reservation.Status != entity.ReservationActive || !reservation.ExpiresAt.After(time.Now()) {
+			apperror.Respond(c, apperror.Conflict("reservation_inactive", "Reservation is no longer active"))
+			return
+		}
+		if err := db.Model(&reservation).Update("status", entity.ReservationReleased).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "reservation_released", nil)})
+	}
+}
+
+// reservedQuantity sums the active reservations of a product or variant,
+// leaving out excludeID (the buyer's own reservation during checkout).
+func reservedQuantity(db *gorm.DB, productID uint, variantID *uint, excludeID uint) (int, error) {
+	query := db.Model(&models.StockReservation{}).
+		Where("product_id = ? AND status = ? AND expires_at > ? AND id <> ?", productID, entity.ReservationActive, time.Now(), excludeID)
+	if variantID != nil {
+		query = query.Where("variant_id = ?", *variantID)
+	} else {
+		query = query.Where("variant_id IS NULL")
+	}
+	var reserved int
+	err := query.Select("COALESCE(SUM(quantity), 0)").Scan(&reserved).Error
+	return reserved, err
+}
+
+// fillAvailability sets Available on products and their loaded variants to
+// stock minus active reservations.
+func fillAvailability(db *gorm.DB, products []models.Product) error {
+	var rows []struct {
+		ProductID uint
+		VariantID *uint
+		Reserved  int
+	}
+	if err := db.Model(&models.StockReservation{}).
+		Select("product_id, variant_id, SUM(quantity) AS reserved").
+		Where("status = ? AND expires_at > ?", entity.ReservationActive, time.Now()).
+		Group("product_id, variant_id").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	productReserved := map[uint]int{}
+	variantReserved := map[uint]int{}
+	for _, row := range rows {
+		if row.VariantID != nil {
+			variantReserved[*row.VariantID] = row.Reserved
+		} else {
+			productReserved[row.ProductID] = row.Reserved
+		}
+	}
+	for i := range products {
+		products[i].Available = max(products[i].Stock-productReserved[products[i].ID], 0)
+		for j := range products[i].Variants {
+			variant := &products[i].Variants[j]
+			variant.Available = max(variant.Stock-variantReserved[variant.ID], 0)
+		}
+	}
+	return nil
+}
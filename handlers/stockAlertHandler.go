@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/i18n"
+	"e-commerce/models"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LowStockItem is a product or variant at or below its reorder threshold.
+type LowStockItem struct {
+	ProductID        uint   `json:"product_id"`
+	VariantID        *uint  `json:"variant_id"`
+	Title            string `json:"title"`
+	SKU              string `json:"sku"`
+	Stock            int    `json:"stock"`
+	ReorderThreshold int    `json:"reorder_threshold"`
+}
+
+// @Summary Low-stock report
+// @Description List products and variants whose stock is at or below the product reorder threshold, lowest stock first
+// @Tags Inventory
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} LowStockItem "Low-stock items"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 403 {object} apperror.Problem "Forbidden"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/low-stock [get]
+func GetLowStockReport(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		items := []LowStockItem{}
+		if err := db.Model(&models.Product{}).
+			Select("products.id AS product_id, products.title, products.stock, products.reorder_threshold").
+			Where("products.reorder_threshold > 0 AND products.stock <= products.reorder_threshold").
+			Where("NOT EXISTS (SELECT 1 FROM product_variants WHERE product_variants.product_id = products.id AND product_variants.deleted_at IS NULL)").
+			Scan(&items).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+
+		var variantItems []LowStockItem
+		if err := db.Model(&models.ProductVariant{}).
+			Select("products.id AS product_id, product_variants.id AS variant_id, products.title, product_variants.sku, product_variants.stock, products.reorder_threshold").
+			Joins("JOIN products ON products.id = product_variants.product_id AND products.deleted_at IS NULL").
+			Where("products.reorder_threshold > 0 AND product_variants.stock <= products.reorder_threshold").
+			Scan(&variantItems).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		items = append(items, variantItems...)
+
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].Stock < items[j].Stock
+		})
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+// @Summary Subscribe to a restock notification
+// @Description Get notified when an out of stock product, or one of its variants, is back in stock
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param variant_id body integer false "Variant ID"
+// @Success 201 {object} models.RestockSubscription "Subscribed"
+// @Failure 400 {object} apperror.Problem "Product is in stock"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/restock-subscriptions [post]
+func SubscribeRestock(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+
+		var userInput struct {
+			VariantID *uint `json:"variant_id"`
+		}
+		if err := c.ShouldBindJSON(&userInput); err != nil && c.Request.ContentLength > 0 {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+
+		stock := existingProduct.Stock
+		subscriptions := db.Where("user_id = ? AND product_id = ? AND notified_at IS NULL", actingUserID(c), existingProduct.ID)
+		if userInput.VariantID != nil {
+			var existingVariant models.ProductVariant
+			if err := db.Where("id = ? AND product_id = ?", *userInput.VariantID, existingProduct.ID).First(&existingVariant).Error; err != nil {
+				apperror.Respond(c, apperror.NotFound("variant_not_found", "Variant not found"))
+				return
+			}
+			stock = existingVariant.Stock
+			subscriptions = subscriptions.Where("variant_id = ?", existingVariant.ID)
+		} else {
+			subscriptions = subscriptions.Where("variant_id IS NULL")
+		}
+		if stock > 0 {
+			apperror.Respond(c, apperror.Validation("product_in_stock", "Product is in stock"))
+			return
+		}
+
+		var subscription models.RestockSubscription
+		if err := subscriptions.First(&subscription).Error; err == nil {
+			c.JSON(http.StatusOK, subscription)
+			return
+		}
+		subscription = models.RestockSubscription{
+			UserID:    actingUserID(c),
+			ProductID: existingProduct.ID,
+			VariantID: userInput.VariantID,
+		}
+		if err := db.Create(&subscription).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, subscription)
+	}
+}
+
+// @Summary Unsubscribe from restock notifications
+// @Tags Inventory
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Success 200 {object} SuccessResponse "Unsubscribed"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/restock-subscriptions [delete]
+func UnsubscribeRestock(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		if err := db.Where("user_id = ? AND product_id = ? AND notified_at IS NULL", actingUserID(c), c.Param("productId")).
+			Delete(&models.RestockSubscription{}).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "back_in_stock_unsubscribed", nil)})
+	}
+}
@@ -1,172 +1,643 @@
 package handlers
 
 import (
-	"e-commerce/helpers"
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/i18n"
+	"e-commerce/metrics"
 	"e-commerce/models"
+	"e-commerce/money"
+	"e-commerce/repository"
+	"e-commerce/services"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// TransactionResponse is a transaction in the transaction history.
+type TransactionResponse struct {
+	ID              uint                    `json:"id"`
+	UserID          uint                    `json:"user_id"`
+	Product         TransactionProduct      `json:"product"`
+	Variant         *TransactionVariant     `json:"variant,omitempty"`
+	Quantity        int                     `json:"quantity"`
+	Subtotal        int                     `json:"subtotal"`
+	Discount        int                     `json:"discount"`
+	CouponID        *uint                   `json:"coupon_id,omitempty"`
+	TaxName         string                  `json:"tax_name"`
+	TaxRate         int                     `json:"tax_rate"`
+	Tax             int                     `json:"tax"`
+	TaxIncluded     bool                    `json:"tax_included"`
+	ShippingCost    int                     `json:"shipping_cost"`
+	ShippingAddress *models.ShippingAddress `json:"shipping_address,omitempty"`
+	TotalPrice      int                     `json:"total_price"`
+	CreatedAt       time.Time               `json:"created_at"`
+}
+
+type TransactionProduct struct {
+	ID         uint   `json:"id"`
+	SKU        string `json:"sku"`
+	Title      string `json:"title"`
+	CategoryID int    `json:"category_id"`
+}
+
+type TransactionVariant struct {
+	ID    uint   `json:"id"`
+	SKU   string `json:"sku"`
+	Size  string `json:"size"`
+	Color string `json:"color"`
+}
+
 // @Summary Get user's transactions
-// @Description Retrieve transactions for the authenticated user
+// @Description Retrieve a page of the authenticated user's transactions, newest first. The total count is returned in the X-Total-Count header
 // @Tags Transactions
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer token"
-// @Success 200 {array} models.TransactionHistory "List of user's transactions"
-// @Failure 401 {object} ErrorResponse "Unauthorized"
-// @Failure 404 {object} ErrorResponse "Transactions not found for the user"
-// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Param from query string false "Earliest purchase date, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "Latest purchase date, RFC 3339 or YYYY-MM-DD (inclusive)"
+// @Param product_id query integer false "Product ID"
+// @Param category_id query integer false "Category ID"
+// @Param min_total query integer false "Minimum total price"
+// @Param max_total query integer false "Maximum total price"
+// @Param sort query string false "Sort column" Enums(created_at, total_price, quantity)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param page query integer false "Page number, starting at 1"
+// @Param page_size query integer false "Transactions per page, at most 100"
+// @Success 200 {array} TransactionResponse "List of user's transactions"
+// @Header 200 {integer} X-Total-Count "Number of matching transactions"
+// @Failure 400 {object} apperror.Problem "Bad request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
 // @Router /transactions/my-transactions [get]
 func GetMyTransaction(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userID, exists := c.Get("id")
-		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User id not found in the context"})
+		db := db.WithContext(c.Request.Context())
+		userID := actingUserID(c)
+		if userID == 0 {
+			apperror.Respond(c, apperror.Unauthorized("missing_user_id", "User id not found in the context"))
 			return
 		}
 
-		var transactions []models.TransactionHistory
-		if err := db.Preload("Product").Where("user_id = ?", userID).Find(&transactions).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Transactions not found for the user"})
-				return
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		filter, err := transactionFilter(c)
+		if err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
 			return
 		}
-
-		c.JSON(http.StatusOK, transactions)
+		filter.UserID = userID
+		searchTransactions(c, db, filter)
 	}
 }
 
 // @Summary Get all transactions
-// @Description Retrieve all transactions (admin access)
+// @Description Retrieve a page of all transactions (admin access), newest first. The total count is returned in the X-Total-Count header
 // @Tags Transactions
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer token"
+// @Param user_id query integer false "User ID"
+// @Param from query string false "Earliest purchase date, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "Latest purchase date, RFC 3339 or YYYY-MM-DD (inclusive)"
+// @Param product_id query integer false "Product ID"
+// @Param category_id query integer false "Category ID"
+// @Param min_total query integer false "Minimum total price"
+// @Param max_total query integer false "Maximum total price"
+// @Param sort query string false "Sort column" Enums(created_at, total_price, quantity)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Param page query integer false "Page number, starting at 1"
+// @Param page_size query integer false "Transactions per page, at most 100"
 // @Security ApiKeyAuth
-// @Success 200 {array} models.TransactionHistory "List of all transactions"
-// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Success 200 {array} TransactionResponse "List of all transactions"
+// @Header 200 {integer} X-Total-Count "Number of matching transactions"
+// @Failure 400 {object} apperror.Problem "Bad request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
 // @Router /transactions/user-transactions [get]
 func GetTransaction(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var TransactionHistory []models.TransactionHistory
-		db.Preload("Products").Find(&TransactionHistory)
-		if len(TransactionHistory) == 0 {
-			c.JSON(http.StatusOK, []string{})
-		} else {
-			c.JSON(http.StatusOK, TransactionHistory)
+		db := db.WithContext(c.Request.Context())
+		filter, err := transactionFilter(c)
+		if err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+		if filter.UserID, err = queryUint(c, "user_id"); err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
+			return
+		}
+		searchTransactions(c, db, filter)
+	}
+}
+
+// @Summary Get a transaction
+// @Description Retrieve a single transaction. Users can only see their own transactions, admins can see any
+// @Tags Transactions
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param transactionId path integer true "Transaction ID"
+// @Success 200 {object} TransactionResponse "Transaction"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 404 {object} apperror.Problem "Transaction not found"
+// @Router /transactions/{transactionId} [get]
+func GetTransactionDetail(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		transactionID, err := strconv.ParseUint(c.Param("transactionId"), 10, 64)
+		if err != nil {
+			apperror.Respond(c, services.ErrTransactionNotFound)
+			return
+		}
+
+		transactionService := services.TransactionService{TransactionRepository: repository.NewTransactionRepo(db)}
+		transaction, err := transactionService.GetTransaction(c.Request.Context(), uint(transactionID), actingUserID(c), isAdmin(c))
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, transactionResponse(*transaction))
+	}
+}
+
+func searchTransactions(c *gin.Context, db *gorm.DB, filter entity.TransactionFilter) {
+	transactionService := services.TransactionService{TransactionRepository: repository.NewTransactionRepo(db)}
+	page, err := transactionService.SearchTransactions(c.Request.Context(), filter)
+	if err != nil {
+		apperror.Respond(c, err)
+		return
+	}
+
+	transactions := make([]TransactionResponse, 0, len(page.Transactions))
+	for _, transaction := range page.Transactions {
+		transactions = append(transactions, transactionResponse(transaction))
+	}
+	c.Header("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	c.Header("X-Page", strconv.Itoa(page.Page))
+	c.Header("X-Page-Size", strconv.Itoa(page.PageSize))
+	c.JSON(http.StatusOK, transactions)
+}
+
+// transactionFilter reads the filter, sort and page query parameters shared
+// by the transaction lists.
+func transactionFilter(c *gin.Context) (entity.TransactionFilter, error) {
+	var filter entity.TransactionFilter
+	var err error
+	if filter.From, filter.To, err = queryDateRange(c); err != nil {
+		return filter, err
+	}
+	if filter.ProductID, err = queryUint(c, "product_id"); err != nil {
+		return filter, err
+	}
+	if filter.CategoryID, err = queryUint(c, "category_id"); err != nil {
+		return filter, err
+	}
+	if filter.MinTotal, err = queryInt(c, "min_total"); err != nil {
+		return filter, err
+	}
+	if filter.MaxTotal, err = queryInt(c, "max_total"); err != nil {
+		return filter, err
+	}
+
+	filter.Sort = c.Query("sort")
+	switch c.Query("order") {
+	case "":
+		filter.Descending = filter.Sort == ""
+	case "asc":
+	case "desc":
+		filter.Descending = true
+	default:
+		return filter, errors.New("order must be asc or desc")
+	}
+
+	for key, value := range map[string]*int{"page": &filter.Page, "page_size": &filter.PageSize} {
+		number, err := queryInt(c, key)
+		if err != nil {
+			return filter, err
+		}
+		if number != nil {
+			if *number < 1 {
+				return filter, fmt.Errorf("%s must be at least 1", key)
+			}
+			*value = *number
 		}
 	}
+	return filter, nil
+}
+
+// queryDateRange reads the from and to query parameters. The returned to is
+// exclusive, so a date-only to includes the whole day.
+func queryDateRange(c *gin.Context) (from, to *time.Time, err error) {
+	if from, _, err = queryTime(c, "from"); err != nil {
+		return nil, nil, err
+	}
+	to, dateOnly, err := queryTime(c, "to")
+	if err != nil || to == nil {
+		return from, nil, err
+	}
+	end := to.Add(time.Nanosecond)
+	if dateOnly {
+		end = to.AddDate(0, 0, 1)
+	}
+	return from, &end, nil
+}
+
+func queryTime(c *gin.Context, key string) (*time.Time, bool, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, false, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return &t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s must be a date (YYYY-MM-DD) or an RFC 3339 time", key)
+	}
+	return &t, false, nil
+}
+
+func queryUint(c *gin.Context, key string) (uint, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a positive number", key)
+	}
+	return uint(number), nil
+}
+
+func queryInt(c *gin.Context, key string) (*int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a number", key)
+	}
+	return &number, nil
+}
+
+func transactionResponse(transaction entity.TransactionHistory) TransactionResponse {
+	id, _ := strconv.ParseUint(transaction.ID, 10, 64)
+	productID, _ := strconv.ParseUint(transaction.Product.ID, 10, 64)
+	response := TransactionResponse{
+		ID:     uint(id),
+		UserID: transaction.UserID,
+		Product: TransactionProduct{
+			ID:         uint(productID),
+			SKU:        transaction.Product.SKU,
+			Title:      transaction.Product.Title,
+			CategoryID: transaction.Product.CategoryID,
+		},
+		Quantity:     transaction.Quantity,
+		Subtotal:     transaction.Subtotal,
+		Discount:     transaction.Discount,
+		CouponID:     transaction.CouponID,
+		TaxName:      transaction.TaxName,
+		TaxRate:      transaction.TaxRate,
+		Tax:          transaction.Tax,
+		TaxIncluded:  transaction.TaxIncluded,
+		ShippingCost: transaction.ShippingCost,
+		TotalPrice:   transaction.TotalPrice,
+		CreatedAt:    transaction.CreatedAt,
+	}
+	if transaction.Variant != nil {
+		response.Variant = &TransactionVariant{
+			ID:    transaction.Variant.ID,
+			SKU:   transaction.Variant.SKU,
+			Size:  transaction.Variant.Size,
+			Color: transaction.Variant.Color,
+		}
+	}
+	if transaction.AddressID != nil || transaction.ShippingAddress.Street != "" {
+		address := models.ShippingAddress(transaction.ShippingAddress)
+		response.ShippingAddress = &address
+	}
+	return response
+}
+
+// PurchaseInput is the request body for buying a product.
+type PurchaseInput struct {
+	ProductID     uint   `json:"product_id" binding:"required"`
+	VariantID     uint   `json:"variant_id"`
+	ReservationID uint   `json:"reservation_id"`
+	Quantity      int    `json:"quantity" binding:"required,gt=0"`
+	CouponCode    string `json:"coupon_code"`
+	AddressID     uint   `json:"address_id"`
 }
 
 // @Summary Create a new transaction
-// @Description Purchase a product and create a transaction record
+// @Description Purchase a product and create a transaction record. The bill shows the subtotal, the coupon discount, the tax on the discounted amount the shipping cost and the total. The tax rate is the product category's rate, or the default rate. Shipping is not taxed
 // @Tags Transactions
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer token"
 // @Param product_id body int true "Product ID to purchase"
+// @Param variant_id body int false "Variant ID to purchase, required when the product has variants"
+// @Param reservation_id body int false "Reservation holding the stock for this purchase"
 // @Param quantity body int true "Quantity of the product to purchase"
+// @Param coupon_code body string false "Discount coupon code"
+// @Param address_id body int false "Address to ship to, the default address is used when empty"
 // @Success 200 {string} string "Purchase successfull"
-// @Failure 400 {object} ErrorResponse "Bad request"
-// @Failure 401 {object} ErrorResponse "Unauthorized"
-// @Failure 404 {object} ErrorResponse "Product not found"
-// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Failure 400 {object} apperror.Problem "Bad request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 402 {object} apperror.Problem "Insufficient balance"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 409 {object} apperror.Problem "Insufficient stock"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
 // @Router /transactions [post]
-func CreateTransaction(db *gorm.DB) gin.HandlerFunc {
+func CreateTransaction(db *gorm.DB, tax services.TaxConfig, shipping services.ShippingCalculator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var userInput struct {
-			ProductID int `json:"product_id"`
-			Quantity  int `json:"quantity"`
-		}
+		db := db.WithContext(c.Request.Context())
+		var userInput PurchaseInput
 		email, exists := c.Get("email")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User email not found in the context"})
+			apperror.Respond(c, apperror.Unauthorized("missing_user_email", "User email not found in the context"))
 			return
 		}
 		// Bind only the specified fields from the JSON request
 		if err := c.ShouldBindJSON(&userInput); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		if userInput.Quantity == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity can't be 0 or empty"})
-			return
-		}
-		if userInput.ProductID == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Product can't be empty"})
+			apperror.Respond(c, apperror.InvalidBody(err))
 			return
 		}
 		var existingProduct models.Product
 		if err := db.Where("id = ?", userInput.ProductID).First(&existingProduct).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
 			return
 		}
-		if existingProduct.Stock == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Product is out of stock"})
+		var existingCategory models.Category
+		if err := db.First(&existingCategory, existingProduct.CategoryID).Error; err != nil {
+			apperror.Respond(c, err)
 			return
 		}
-		if userInput.Quantity > existingProduct.Stock {
-			errorMessage := fmt.Sprintf("Insufficient stock. Only %d stocks left.", existingProduct.Stock)
-			c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage})
+		var existingVariant *models.ProductVariant
+		price, stock := existingProduct.Price, existingProduct.Stock
+		if userInput.VariantID != 0 {
+			existingVariant = &models.ProductVariant{}
+			if err := db.Where("id = ? AND product_id = ?", userInput.VariantID, existingProduct.ID).First(existingVariant).Error; err != nil {
+				apperror.Respond(c, apperror.NotFound("variant_not_found", "Variant not found"))
+				return
+			}
+			stock = existingVariant.Stock
+			if existingVariant.Price != nil {
+				price = *existingVariant.Price
+			}
+		} else {
+			var variantCount int64
+			if err := db.Model(&models.ProductVariant{}).Where("product_id = ?", existingProduct.ID).Count(&variantCount).Error; err != nil {
+				apperror.Respond(c, err)
+				return
+			}
+			if variantCount > 0 {
+				apperror.Respond(c, apperror.Validation("variant_required", "This product has variants, variant_id is required"))
+				return
+			}
+		}
+		var reservation *models.StockReservation
+		if userInput.ReservationID != 0 {
+			reservation = &models.StockReservation{}
+			if err := db.Where("id = ? AND user_id = ?", userInput.ReservationID, actingUserID(c)).First(reservation).Error; err != nil {
+				apperror.Respond(c, apperror.NotFound("reservation_not_found", "Reservation not found"))
+				return
+			}
+			if reservation.Status != entity.ReservationActive || !reservation.ExpiresAt.After(time.Now()) {
+				apperror.Respond(c, apperror.Validation("reservation_expired", "Reservation has expired"))
+				return
+			}
+			if reservation.ProductID != existingProduct.ID || !sameVariant(reservation.VariantID, variantID(existingVariant)) || reservation.Quantity != userInput.Quantity {
+				apperror.Respond(c, apperror.Validation("reservation_mismatch", "Reservation does not match the purchase"))
+				return
+			}
+		}
+		reserved, err := reservedQuantity(db, existingProduct.ID, variantID(existingVariant), reservationID(reservation))
+		if err != nil {
+			apperror.Respond(c, err)
 			return
 		}
-		var existingUser models.User
-		if err := db.Where("email = ?", email).First(&existingUser).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		stock = max(stock-reserved, 0)
+		if stock == 0 {
+			metrics.OutOfStockRejections.WithLabelValues("purchase").Inc()
+			apperror.Respond(c, apperror.InsufficientStock(0))
 			return
 		}
-		totalPrice := userInput.Quantity * existingProduct.Price
-		if existingUser.Balance < totalPrice {
-			errorMessage := fmt.Sprintf("Insufficient balance. Total price: %s, your balance: %s", helpers.FormatRupiah(totalPrice), helpers.FormatRupiah(existingUser.Balance))
-			c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage})
+		if userInput.Quantity > stock {
+			metrics.OutOfStockRejections.WithLabelValues("purchase").Inc()
+			apperror.Respond(c, apperror.InsufficientStock(stock))
 			return
 		}
-		existingProduct.Stock -= userInput.Quantity
-		if err := db.Save(&existingProduct).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		var existingUser models.User
+		if err := db.Where("email = ?", email).First(&existingUser).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("user_not_found", "User not found"))
 			return
 		}
-		existingUser.Balance -= totalPrice
-		if err := db.Save(&existingUser).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		address, err := shippingAddress(db, existingUser.ID, userInput.AddressID)
+		if err != nil {
+			if userInput.AddressID != 0 {
+				apperror.Respond(c, apperror.NotFound("address_not_found", "Address not found"))
+				return
+			}
+			apperror.Respond(c, apperror.Validation("shipping_address_required", "A shipping address is required, add one to your address book"))
 			return
 		}
-		updatedTransaction := models.TransactionHistory{
+		line := services.PurchaseLine{
 			ProductID:  existingProduct.ID,
-			UserID:     existingUser.ID,
+			CategoryID: existingProduct.CategoryID,
 			Quantity:   userInput.Quantity,
-			TotalPrice: totalPrice,
+			UnitPrice:  price,
 		}
-		if err := db.Create(&updatedTransaction).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		var existingCoupon *models.Coupon
+		discount := 0
+		if userInput.CouponCode != "" {
+			existingCoupon = &models.Coupon{}
+			if err := db.Where("code = ?", services.NormalizeCouponCode(userInput.CouponCode)).First(existingCoupon).Error; err != nil {
+				apperror.Respond(c, apperror.NotFound("coupon_not_found", "Coupon not found"))
+				return
+			}
+			usage, err := couponUsage(db, existingCoupon.ID, existingUser.ID)
+			if err != nil {
+				apperror.Respond(c, err)
+				return
+			}
+			if discount, err = services.CalculateDiscount(couponEntity(*existingCoupon), line, usage, time.Now()); err != nil {
+				apperror.Respond(c, apperror.Invalid(err))
+				return
+			}
+		}
+		subtotal := line.Subtotal()
+		shipment := services.Shipment{
+			Province:   address.Province,
+			City:       address.City,
+			PostalCode: address.PostalCode,
+			Weight:     existingProduct.Weight * userInput.Quantity,
+		}
+		taxBreakdown := tax.Calculate(subtotal-discount, existingCategory.TaxRate)
+		shipment.Subtotal = subtotal - discount
+		shippingCost, err := shipping.Calculate(shipment)
+		if err != nil {
+			apperror.Respond(c, apperror.Invalid(err))
 			return
 		}
-		var existingCategory models.Category
-		if err := db.Model(&existingProduct).Association("Category").Find(&existingCategory); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		totalPrice := taxBreakdown.Total + shippingCost
+		if existingUser.Balance < totalPrice {
+			apperror.Respond(c, apperror.InsufficientBalance(totalPrice, existingUser.Balance))
 			return
 		}
-		existingCategory.SoldProductAmount += userInput.Quantity
-
-		if err := db.Save(&existingCategory).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		saleMovement := models.InventoryMovement{
+			ProductID: existingProduct.ID,
+			VariantID: variantID(existingVariant),
+			UserID:    existingUser.ID,
+			Type:      services.MovementSale,
+			Quantity:  -userInput.Quantity,
+		}
+		var (
+			redemption         *models.CouponRedemption
+			updatedTransaction models.TransactionHistory
+			issued             *models.Invoice
+			available          int
+		)
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			stock, err := lockStock(tx, saleMovement.ProductID, saleMovement.VariantID)
+			if err != nil {
+				return err
+			}
+			reserved, err := reservedQuantity(tx, saleMovement.ProductID, saleMovement.VariantID, reservationID(reservation))
+			if err != nil {
+				return err
+			}
+			available = max(stock-reserved, 0)
+			if userInput.Quantity > available {
+				return errInsufficientStock
+			}
+			if existingCoupon != nil {
+				if redemption, err = redeemCoupon(tx, existingCoupon.ID, existingUser.ID, line); err != nil {
+					return err
+				}
+				// The coupon may have changed since it was checked above
+				discount = redemption.Discount
+				taxBreakdown = tax.Calculate(subtotal-discount, existingCategory.TaxRate)
+				shipment.Subtotal = subtotal - discount
+				if shippingCost, err = shipping.Calculate(shipment); err != nil {
+					return apperror.Invalid(err)
+				}
+				totalPrice = taxBreakdown.Total + shippingCost
+			}
+			// Deduct only while the balance still covers the total, so
+			// concurrent purchases can't spend the same money twice
+			deducted := tx.Model(&models.User{}).
+				Where("id = ? AND balance >= ?", existingUser.ID, totalPrice).
+				Update("balance", gorm.Expr("balance - ?", totalPrice))
+			if deducted.Error != nil {
+				return deducted.Error
+			}
+			if deducted.RowsAffected == 0 {
+				var balance int
+				if err := tx.Model(&models.User{}).Select("balance").Where("id = ?", existingUser.ID).Scan(&balance).Error; err != nil {
+					return err
+				}
+				return apperror.InsufficientBalance(totalPrice, balance)
+			}
+			updatedTransaction = models.TransactionHistory{
+				ProductID:       existingProduct.ID,
+				VariantID:       variantID(existingVariant),
+				UserID:          existingUser.ID,
+				Quantity:        userInput.Quantity,
+				Subtotal:        subtotal,
+				Discount:        discount,
+				TaxName:         taxBreakdown.Name,
+				TaxRate:         taxBreakdown.Rate,
+				TaxBase:         taxBreakdown.Base,
+				Tax:             taxBreakdown.Tax,
+				TaxIncluded:     taxBreakdown.Included,
+				ShippingCost:    shippingCost,
+				AddressID:       &address.ID,
+				ShippingAddress: address.ShippingAddress,
+				TotalPrice:      totalPrice,
+			}
+			if existingCoupon != nil {
+				updatedTransaction.CouponID = &existingCoupon.ID
+			}
+			if err := tx.Create(&updatedTransaction).Error; err != nil {
+				return err
+			}
+			if issued, err = issueInvoice(tx, updatedTransaction.ID, updatedTransaction.CreatedAt); err != nil {
+				return err
+			}
+			if redemption != nil {
+				if err := tx.Model(redemption).Update("transaction_id", updatedTransaction.ID).Error; err != nil {
+					return err
+				}
+			}
+			if reservation != nil {
+				if err := tx.Model(reservation).Update("status", entity.ReservationConsumed).Error; err != nil {
+					return err
+				}
+			}
+			saleMovement.TransactionID = &updatedTransaction.ID
+			if err := applyStockMovement(tx, &saleMovement); err != nil {
+				return err
+			}
+			return tx.Model(&existingCategory).Update("sold_product_amount", gorm.Expr("sold_product_amount + ?", userInput.Quantity)).Error
+		}); err != nil {
+			if errors.Is(err, errInsufficientStock) {
+				metrics.OutOfStockRejections.WithLabelValues("purchase").Inc()
+				apperror.Respond(c, apperror.InsufficientStock(available))
+				return
+			}
+			apperror.Respond(c, err)
 			return
 		}
+		metrics.Purchases.Inc()
+		metrics.Revenue.Add(float64(totalPrice))
+		bill := gin.H{
+			"transaction_id": updatedTransaction.ID,
+			"invoice_number": issued.Number,
+			"subtotal":       subtotal,
+			"discount":       discount,
+			"tax":            taxBreakdown,
+			"shipping_cost":  shippingCost,
+			"ship_to":        address.ShippingAddress,
+			"total_price":    totalPrice,
+			"currency":       money.BaseCurrency,
+			"quantity":       userInput.Quantity,
+			"product_title":  existingProduct.Title,
+		}
+		if existingVariant != nil {
+			bill["sku"] = existingVariant.SKU
+		}
+		if existingCoupon != nil {
+			bill["coupon_code"] = existingCoupon.Code
+		}
 		c.JSON(http.StatusOK, gin.H{
-			"message": "You have successfully purchased the product",
-			"transaction_bill": gin.H{
-				"total_price":   totalPrice,
-				"quantity":      userInput.Quantity,
-				"product_title": existingProduct.Title,
-			},
+			"message":          i18n.T(c.Request.Context(), "product_purchased", nil),
+			"transaction_bill": bill,
 		})
 	}
 }
+
+func reservationID(reservation *models.StockReservation) uint {
+	if reservation == nil {
+		return 0
+	}
+	return reservation.ID
+}
+
+func sameVariant(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func variantID(variant *models.ProductVariant) *uint {
+	if variant == nil {
+		return nil
+	}
+	return &variant.ID
+}
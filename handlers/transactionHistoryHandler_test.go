@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/services"
+	"e-commerce/validation"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// unreachableDB never connects, so it only serves handlers that reject the
+// request before touching the database.
+func unreachableDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1"), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	return db
+}
+
+func TestCreateTransactionRejectsNonPositiveQuantity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	binding.Validator = validation.Binding{}
+	router := gin.New()
+	router.POST("/transactions", func(c *gin.Context) {
+		c.Set("email", "felix@example.com")
+	}, CreateTransaction(unreachableDB(t), services.TaxConfig{}, services.FlatRateShipping{}))
+
+	for _, body := range []string{
+		`{"product_id": 1, "quantity": -5}`,
+		`{"product_id": 1, "quantity": 0}`,
+		`{"product_id": 1}`,
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+		var problem apperror.Problem
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, "invalid_fields", problem.Code, body)
+		require.Len(t, problem.Errors, 1, body)
+		assert.Equal(t, "quantity", problem.Errors[0].Field, body)
+	}
+}
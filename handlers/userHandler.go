@@ -1,70 +1,68 @@
 package handlers
 
 import (
-	"e-commerce/helpers"
+	"e-commerce/apperror"
+	"e-commerce/i18n"
+	"e-commerce/metrics"
 	"e-commerce/models"
-	"fmt"
+	"e-commerce/money"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// TopUpInput is the request body for updating the user's balance.
+type TopUpInput struct {
+	Balance int `json:"balance" binding:"required,balance"`
+}
+
 // Updates User's balance
 // @Summary Updates the user's balance
 // @Produce json
 // @Consumes json
-// @Param email body int true "Balance"
-// @Header 200 {string} Token "API token for authentication"
-// @Success 201 {object} models.User "User registered successfully"
-// @Router /users/register [post]
+// @Param Authorization header string true "Bearer token"
+// @Param balance body int true "Balance"
+// @Success 200 {object} map[string]string "Balance updated"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Router /topup [patch]
 func UpdateUserBalance(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
 		// Retrieve user email from the context
 		userEmail, exists := c.Get("email")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User email not found in the context"})
+			apperror.Respond(c, apperror.Unauthorized("missing_user_email", "User email not found in the context"))
 			return
 		}
 
 		// Retrieve existing user from the database using email
 		var existingUser models.User
 		if err := db.Where("email = ?", userEmail).First(&existingUser).Error; err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			apperror.Respond(c, apperror.NotFound("user_not_found", "User not found"))
 			return
 		}
 
-		// Create a struct to hold only the necessary fields for update
-		var userInput struct {
-			Balance int `json:"balance"`
-		}
+		var userInput TopUpInput
 
 		// Bind only the specified fields from the JSON request
 		if err := c.ShouldBindJSON(&userInput); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-		if userInput.Balance == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Balance cannot be empty or zero"})
+			apperror.Respond(c, apperror.InvalidBody(err))
 			return
 		}
-
-		// Custom validation for the 'Balance' field
-		if err := helpers.ValidateBalance(userInput.Balance); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
-		}
-
 		// Update the user's balance
 		existingUser.Balance = userInput.Balance
 
 		// Save the changes to the database
 		if err := db.Save(&existingUser).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			apperror.Respond(c, err)
 			return
 		}
 
+		metrics.TopUps.Inc()
+
 		// Respond with the updated user
-		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Your balance has been successfully updated to Rp %d", existingUser.Balance)})
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "balance_updated", i18n.Params{"balance": money.IDR(existingUser.Balance)})})
 	}
 }
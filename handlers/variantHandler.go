@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/i18n"
+	"e-commerce/models"
+	"e-commerce/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type variantInput struct {
+	SKU   string `json:"sku" binding:"required"`
+	Size  string `json:"size"`
+	Color string `json:"color"`
+	Price *int   `json:"price" binding:"omitempty,price"`
+	Stock int    `json:"stock" binding:"gte=0"`
+}
+
+// @Summary Get product variants
+// @Description Get every variant of a product
+// @Tags Variants
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Success 200 {array} models.ProductVariant "List of variants"
+// @Failure 401 {object} apperror.Problem "Unauthorized"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Router /products/{productId}/variants [get]
+func GetVariants(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+
+		var variants []models.ProductVariant
+		if err := db.Where("product_id = ?", existingProduct.ID).Order("id").Find(&variants).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, variants)
+	}
+}
+
+// @Summary Create a product variant
+// @Description Create a variant with its own SKU, options, price override and stock
+// @Tags Variants
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param sku body string true "Variant SKU"
+// @Param size body string false "Size option"
+// @Param color body string false "Color option"
+// @Param price body integer false "Price override, the product price is used when empty"
+// @Param stock body integer true "Variant stock"
+// @Success 201 {object} models.ProductVariant "Variant created successfully"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 409 {object} apperror.Problem "SKU already exists"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/variants [post]
+func CreateVariant(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+
+		var userInput variantInput
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+
+		var existingVariant models.ProductVariant
+		if err := db.Where("sku = ?", userInput.SKU).First(&existingVariant).Error; err == nil {
+			apperror.Respond(c, apperror.Conflict("sku_exists", "SKU already exists!"))
+			return
+		}
+		newVariant := models.ProductVariant{
+			ProductID: existingProduct.ID,
+			SKU:       userInput.SKU,
+			Size:      userInput.Size,
+			Color:     userInput.Color,
+			Price:     userInput.Price,
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return createVariantWithStock(tx, &newVariant, userInput.Stock, actingUserID(c))
+		})
+		if err != nil {
+			apperror.Respond(c, apperror.Internal(err, "variant_create_failed", "Failed to create variant"))
+			return
+		}
+		c.JSON(http.StatusCreated, newVariant)
+	}
+}
+
+// @Summary Create a variant matrix
+// @Description Create one variant for every size and color combination. SKUs are built from sku_prefix, size and color.
+// @Tags Variants
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param sku_prefix body string false "SKU prefix, defaults to the product title"
+// @Param sizes body []string false "Sizes"
+// @Param colors body []string false "Colors"
+// @Param price body integer false "Price override for every variant"
+// @Param stock body integer true "Stock for every variant"
+// @Success 201 {array} models.ProductVariant "Variants created successfully"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 404 {object} apperror.Problem "Product not found"
+// @Failure 409 {object} apperror.Problem "SKU already exists"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/variants/matrix [post]
+func CreateVariantMatrix(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingProduct models.Product
+		if err := db.First(&existingProduct, c.Param("productId")).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("product_not_found", "Product not found"))
+			return
+		}
+
+		var userInput struct {
+			SKUPrefix string   `json:"sku_prefix"`
+			Sizes     []string `json:"sizes"`
+			Colors    []string `json:"colors"`
+			Price     *int     `json:"price" binding:"omitempty,price"`
+			Stock     int      `json:"stock" binding:"gte=0"`
+		}
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+		if userInput.SKUPrefix == "" {
+			userInput.SKUPrefix = existingProduct.Title
+		}
+
+		matrix := services.BuildVariantMatrix(existingProduct.ID, services.VariantMatrixInput{
+			SKUPrefix: userInput.SKUPrefix,
+			Sizes:     userInput.Sizes,
+			Colors:    userInput.Colors,
+			Price:     userInput.Price,
+			Stock:     userInput.Stock,
+		})
+		if len(matrix) == 0 {
+			apperror.Respond(c, apperror.Validation("variant_options_required", "At least one size or color is required"))
+			return
+		}
+
+		newVariants := make([]models.ProductVariant, 0, len(matrix))
+		for _, variant := range matrix {
+			newVariants = append(newVariants, variantModel(variant))
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for i := range newVariants {
+				var existingVariant models.ProductVariant
+				if err := tx.Where("sku = ?", newVariants[i].SKU).First(&existingVariant).Error; err == nil {
+					return errSKUExists
+				}
+				stock := newVariants[i].Stock
+				newVariants[i].Stock = 0
+				if err := createVariantWithStock(tx, &newVariants[i], stock, actingUserID(c)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if errors.Is(err, errSKUExists) {
+			apperror.Respond(c, apperror.Conflict("sku_exists", "SKU already exists!"))
+			return
+		}
+		if err != nil {
+			apperror.Respond(c, apperror.Internal(err, "variant_create_failed", "Failed to create variants"))
+			return
+		}
+		c.JSON(http.StatusCreated, newVariants)
+	}
+}
+
+// @Summary Update a product variant
+// @Tags Variants
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param variantId path integer true "Variant ID"
+// @Param sku body string true "Variant SKU"
+// @Param size body string false "Size option"
+// @Param color body string false "Color option"
+// @Param price body integer false "Price override, the product price is used when empty"
+// @Param stock body integer true "Variant stock"
+// @Success 200 {object} models.ProductVariant "Updated variant"
+// @Failure 400 {object} apperror.Problem "Bad Request"
+// @Failure 404 {object} apperror.Problem "Variant not found"
+// @Failure 409 {object} apperror.Problem "SKU already exists"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/variants/{variantId} [put]
+func UpdateVariant(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingVariant models.ProductVariant
+		if err := db.Where("id = ? AND product_id = ?", c.Param("variantId"), c.Param("productId")).First(&existingVariant).Error; err != nil {
+			apperror.Respond(c, apperror.NotFound("variant_not_found", "Variant not found"))
+			return
+		}
+
+		var userInput variantInput
+		if err := c.ShouldBindJSON(&userInput); err != nil {
+			apperror.Respond(c, apperror.InvalidBody(err))
+			return
+		}
+
+		var duplicateVariant models.ProductVariant
+		if err := db.Where("sku = ? AND id <> ?", userInput.SKU, existingVariant.ID).First(&duplicateVariant).Error; err == nil {
+			apperror.Respond(c, apperror.Conflict("sku_exists", "SKU already exists!"))
+			return
+		}
+
+		existingVariant.SKU = userInput.SKU
+		existingVariant.Size = userInput.Size
+		existingVariant.Color = userInput.Color
+		existingVariant.Price = userInput.Price
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Omit("stock").Save(&existingVariant).Error; err != nil {
+				return err
+			}
+			// existingVariant.Stock may be stale by now; only the locked
+			// value is safe to diff against.
+			current, err := lockStock(tx, existingVariant.ProductID, &existingVariant.ID)
+			if err != nil {
+				return err
+			}
+			if delta := userInput.Stock - current; delta != 0 {
+				return applyStockMovement(tx, &models.InventoryMovement{
+					ProductID: existingVariant.ProductID,
+					VariantID: &existingVariant.ID,
+					UserID:    actingUserID(c),
+					Type:      services.MovementAdjustment,
+					Quantity:  delta,
+					Note:      "Variant update",
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		existingVariant.Stock = userInput.Stock
+		c.JSON(http.StatusOK, existingVariant)
+	}
+}
+
+// @Summary Delete a product variant
+// @Tags Variants
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param productId path integer true "Product ID"
+// @Param variantId path integer true "Variant ID"
+// @Success 200 {object} SuccessResponse "Variant has been successfully deleted"
+// @Failure 404 {object} apperror.Problem "Variant not found"
+// @Failure 500 {object} apperror.Problem "Internal Server Error"
+// @Router /products/{productId}/variants/{variantId} [delete]
+func DeleteVariant(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := db.WithContext(c.Request.Context())
+		var existingVariant models.ProductVariant
+		if err := db.Where("id = ? AND product_id = ?", c.Param("variantId"), c.Param("productId")).First(&existingVariant).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				apperror.Respond(c, apperror.NotFound("variant_not_found", "Variant not found"))
+				return
+			}
+			apperror.Respond(c, err)
+			return
+		}
+
+		if err := db.Delete(&existingVariant).Error; err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "variant_deleted", nil)})
+	}
+}
+
+var errSKUExists = errors.New("sku already exists")
+
+func variantModel(variant entity.ProductVariant) models.ProductVariant {
+	return models.ProductVariant{
+		ProductID: variant.ProductID,
+		SKU:       variant.SKU,
+		Size:      variant.Size,
+		Color:     variant.Color,
+		Price:     variant.Price,
+		Stock:     variant.Stock,
+	}
+}
+
+// createVariantWithStock creates variant and journals its initial stock.
+func createVariantWithStock(tx *gorm.DB, variant *models.ProductVariant, stock int, userID uint) error {
+	if err := tx.Create(variant).Error; err != nil {
+		return err
+	}
+	if stock != 0 {
+		if err := applyStockMovement(tx, &models.InventoryMovement{
+			ProductID: variant.ProductID,
+			VariantID: &variant.ID,
+			UserID:    userID,
+			Type:      services.MovementReceipt,
+			Quantity:  stock,
+			Note:      "Initial stock",
+		}); err != nil {
+			return err
+		}
+	}
+	variant.Stock = stock
+	return nil
+}
@@ -1,23 +1,15 @@
 package helpers
 
-import (
-	"strconv"
-	"strings"
-)
+import "e-commerce/money"
 
-// FormatRupiah formats an integer as Rupiah
+// FormatRupiah formats an integer as Rupiah the Indonesian way, e.g.
+// Rp1.500.000
 func FormatRupiah(amount int) string {
-	amountStr := strconv.FormatInt(int64(amount), 10)
-	parts := strings.Split(amountStr, ".")
-	integerPart := parts[0]
-	formattedInteger := addCommas(integerPart)
-	return "Rp. " + formattedInteger
+	return money.IDR(amount).String()
 }
 
-func addCommas(s string) string {
-	n := len(s)
-	if n <= 3 {
-		return s
-	}
-	return addCommas(s[:n-3]) + "," + s[n-3:]
+// FormatRupiahIn formats an integer as Rupiah for readers of locale, e.g.
+// Rp1.500.000 for "id" and Rp1,500,000 for "en".
+func FormatRupiahIn(amount int, locale string) string {
+	return money.Format(money.IDR(amount), money.LookupLocale(locale))
 }
@@ -0,0 +1,181 @@
+package i18n
+
+// english is the reference catalog. Error messages are keyed by their
+// apperror code; "status.<code>" are problem titles, "field.<rule>" field
+// errors, "term.<word>" words used inside other messages and
+// "notification.<event>.<part>" the stock alerts sent by the dispatcher.
+var english = map[string]string{
+	"status.400": "Bad Request",
+	"status.401": "Unauthorized",
+	"status.402": "Payment Required",
+	"status.403": "Forbidden",
+	"status.404": "Not Found",
+	"status.409": "Conflict",
+	"status.413": "Request Entity Too Large",
+	"status.415": "Unsupported Media Type",
+	"status.422": "Unprocessable Entity",
+	"status.500": "Internal Server Error",
+	"status.504": "Gateway Timeout",
+
+	"address_field_required":      "{field} cannot be empty",
+	"address_not_found":           "address not found",
+	"admin_required":              "admin access required",
+	"categories_not_found":        "categories not found",
+	"category_create_failed":      "failed to create category",
+	"category_has_products":       "category still has {count} products, delete them with mode=cascade or move them with mode=reassign",
+	"category_not_found":          "category not found",
+	"category_type_exists":        "category type already exists",
+	"category_type_required":      "category type cannot be empty",
+	"coupon_code_exists":          "coupon code already exists",
+	"coupon_code_required":        "coupon code cannot be empty",
+	"coupon_create_failed":        "failed to create coupon",
+	"coupon_expired":              "coupon has expired",
+	"coupon_fully_redeemed":       "coupon has been fully redeemed",
+	"coupon_inactive":             "coupon is not active",
+	"coupon_minimum_quantity":     "buy more than {quantity} to get {quantity} free",
+	"coupon_minimum_spend":        "minimum spend for this coupon is {amount}",
+	"coupon_not_applicable":       "coupon does not apply to this {target}",
+	"coupon_not_found":            "coupon not found",
+	"coupon_not_started":          "coupon is not valid yet",
+	"coupon_usage_limit_reached":  "coupon usage limit reached",
+	"duplicate_currency":          "currency {currency} is listed more than once",
+	"email_exists":                "email already exists",
+	"empty_import_file":           "import file is empty",
+	"image_dimensions_too_large":  "image must not be larger than {max_width}x{max_height} pixels or {max_pixels} pixels in total",
+	"image_not_found":             "image not found",
+	"image_required":              "image file is required",
+	"image_save_failed":           "failed to save image",
+	"image_store_failed":          "failed to store image",
+	"image_too_large":             "image must not be larger than 5 MB",
+	"incorrect_password":          "incorrect password",
+	"insufficient_balance":        "insufficient balance, total price: {total}, your balance: {balance}",
+	"insufficient_stock":          "insufficient stock, only {available} left",
+	"internal_error":              "internal server error",
+	"invalid_balance":             "invalid balance",
+	"invalid_body":                "request body is not valid JSON",
+	"invalid_coupon_limits":       "coupon limits can't be negative",
+	"invalid_coupon_percentage":   "percentage must be between 1 and 100",
+	"invalid_coupon_period":       "coupon must end after it starts",
+	"invalid_coupon_type":         "invalid coupon type",
+	"invalid_coupon_value":        "coupon value must be greater than zero",
+	"invalid_credentials":         "invalid email or password",
+	"invalid_delete_mode":         "mode must be one of restrict, cascade or reassign",
+	"invalid_email":               "invalid email address",
+	"invalid_fields":              "invalid fields",
+	"invalid_format":              "format must be one of {formats}",
+	"invalid_image":               "image could not be decoded",
+	"invalid_image_order":         "image_ids must list every image of the product exactly once",
+	"invalid_import_format":       "format must be csv or json",
+	"invalid_job_name":            "name must be lower case letters, digits, dashes and underscores",
+	"invalid_metrics_token":       "invalid metrics token",
+	"invalid_movement_type":       "invalid movement type",
+	"invalid_period_days":         "period_days must not be negative",
+	"invalid_postal_code":         "postal code must be 5 digits",
+	"invalid_price":               "invalid price",
+	"invalid_price_change_end":    "price change must end after it starts",
+	"invalid_price_change_start":  "price change must start in the future",
+	"invalid_product":             "invalid product input",
+	"invalid_product_id":          "invalid product ID",
+	"invalid_quantity":            "quantity must be greater than zero",
+	"invalid_report":              "invalid report",
+	"invalid_request":             "invalid request",
+	"invalid_reservation":         "invalid reservation input",
+	"invalid_signature":           "invalid or expired signature",
+	"invalid_target_category":     "a different target_category_id is required to reassign products",
+	"invalid_tax_rate":            "tax rate must be between 0 and 10000 (100%)",
+	"invalid_token":               "invalid token",
+	"invalid_token_claims":        "failed to get claims from token",
+	"invalid_transaction":         "invalid transaction input",
+	"invalid_transaction_filter":  "invalid transaction filter",
+	"invalid_user_id":             "invalid user ID",
+	"invalid_variant":             "invalid variant input",
+	"invalid_weight":              "weight can't be negative",
+	"job_create_failed":           "failed to create job",
+	"job_disabled":                "job is disabled",
+	"job_name_exists":             "job name already exists",
+	"job_not_found":               "job not found",
+	"missing_import_column":       "missing {column} column",
+	"missing_user_email":          "user email not found in the context",
+	"missing_user_id":             "user id not found in the context",
+	"negative_stock":              "stock cannot go below zero",
+	"password_hash_failed":        "failed to hash password",
+	"price_change_overlap":        "price change overlaps another scheduled change",
+	"price_change_started":        "price change has already started",
+	"product_create_failed":       "failed to create product",
+	"product_in_stock":            "product is in stock",
+	"product_not_found":           "product not found",
+	"products_not_found":          "products not found",
+	"reservation_expired":         "reservation has expired",
+	"reservation_inactive":        "reservation is no longer active",
+	"reservation_mismatch":        "reservation does not match the purchase",
+	"reservation_not_found":       "reservation not found",
+	"rupiah_price_not_allowed":    "rupiah prices are set on the product itself",
+	"sale_adjustment_not_allowed": "sales are recorded by purchases",
+	"scheduled_change_not_found":  "scheduled change not found",
+	"shipping_address_required":   "a shipping address is required, add one to your address book",
+	"shipping_unavailable":        "no shipping to {province}",
+	"sku_exists":                  "SKU already exists",
+	"target_category_not_found":   "target category not found",
+	"thumbnail_failed":            "failed to create thumbnail",
+	"thumbnail_store_failed":      "failed to store thumbnail",
+	"timeout":                     "the request took too long",
+	"title_exists":                "title already exists",
+	"token_generation_failed":     "error while generating token",
+	"transaction_not_found":       "transaction not found",
+	"unknown_category":            "category_id does not refer to an existing category",
+	"unknown_job_type":            "unknown job type {type}",
+	"unsupported_image_type":      "only JPEG, PNG and GIF images are allowed",
+	"user_create_failed":          "failed to create user",
+	"user_not_found":              "user not found",
+	"variant_create_failed":       "failed to create variants",
+	"variant_not_found":           "variant not found",
+	"variant_options_required":    "at least one size or color is required",
+	"variant_required":            "this product has variants, variant_id is required",
+	"weak_password":               "password is too weak",
+
+	"field.balance":    "{field} must be between {min} and {max}",
+	"field.before":     "{field} must be before {other}",
+	"field.email":      "{field} must be a valid email address",
+	"field.exists":     "{field} does not refer to an existing record",
+	"field.gt":         "{field} must be greater than {value}",
+	"field.invalid":    "{field} is invalid",
+	"field.ltefield":   "{field} must not be greater than {other}",
+	"field.max":        "{field} must be at most {max}",
+	"field.max_length": "{field} must be at most {max} characters",
+	"field.min":        "{field} must be at least {min}",
+	"field.min_length": "{field} must be at least {min} characters",
+	"field.oneof":      "{field} must be one of {values}",
+	"field.password":   "{field} must be {min} to {max} characters and mix at least two of lower case, upper case, digits and symbols",
+	"field.price":      "{field} must be between {min} and {max}",
+	"field.required":   "{field} is required",
+
+	"term.category":       "category",
+	"term.city":           "city",
+	"term.phone":          "phone",
+	"term.postal code":    "postal code",
+	"term.product":        "product",
+	"term.province":       "province",
+	"term.recipient name": "recipient name",
+	"term.street":         "street",
+
+	"address_deleted":            "Address has been successfully deleted",
+	"back_in_stock_unsubscribed": "You will no longer be notified when this product is back in stock",
+	"balance_updated":            "Your balance has been successfully updated to {balance}",
+	"category_deleted":           "Category has been successfully deleted",
+	"coupon_deleted":             "Coupon has been successfully deleted",
+	"gallery_reordered":          "Gallery order has been successfully updated",
+	"image_deleted":              "Image has been successfully deleted",
+	"job_deleted":                "Job has been successfully deleted",
+	"price_change_cancelled":     "Price change has been cancelled",
+	"product_deleted":            "Product has been successfully deleted",
+	"product_purchased":          "You have successfully purchased the product",
+	"reservation_released":       "Reservation has been successfully released",
+	"variant_deleted":            "Variant has been successfully deleted",
+
+	"notification.back_in_stock.message": "Hi {name}, {title} is available again.",
+	"notification.back_in_stock.subject": "{title} is back in stock",
+	"notification.low_stock.message":     "{title} has {stock} left, at or below its reorder threshold of {threshold}.",
+	"notification.low_stock.subject":     "Low stock: {title}",
+	"notification.out_of_stock.message":  "{title} is out of stock.",
+	"notification.out_of_stock.subject":  "Out of stock: {title}",
+}
@@ -0,0 +1,178 @@
+package i18n
+
+// indonesian mirrors english key for key.
+var indonesian = map[string]string{
+	"status.400": "Permintaan Tidak Valid",
+	"status.401": "Tidak Terautentikasi",
+	"status.402": "Pembayaran Diperlukan",
+	"status.403": "Akses Ditolak",
+	"status.404": "Tidak Ditemukan",
+	"status.409": "Konflik",
+	"status.413": "Permintaan Terlalu Besar",
+	"status.415": "Jenis Media Tidak Didukung",
+	"status.422": "Entitas Tidak Dapat Diproses",
+	"status.500": "Kesalahan Server Internal",
+	"status.504": "Waktu Habis",
+
+	"address_field_required":      "{field} tidak boleh kosong",
+	"address_not_found":           "alamat tidak ditemukan",
+	"admin_required":              "memerlukan akses admin",
+	"categories_not_found":        "kategori tidak ditemukan",
+	"category_create_failed":      "gagal membuat kategori",
+	"category_has_products":       "kategori masih memiliki {count} produk, hapus dengan mode=cascade atau pindahkan dengan mode=reassign",
+	"category_not_found":          "kategori tidak ditemukan",
+	"category_type_exists":        "jenis kategori sudah ada",
+	"category_type_required":      "jenis kategori tidak boleh kosong",
+	"coupon_code_exists":          "kode kupon sudah ada",
+	"coupon_code_required":        "kode kupon tidak boleh kosong",
+	"coupon_create_failed":        "gagal membuat kupon",
+	"coupon_expired":              "kupon sudah kedaluwarsa",
+	"coupon_fully_redeemed":       "kuota kupon sudah habis",
+	"coupon_inactive":             "kupon tidak aktif",
+	"coupon_minimum_quantity":     "beli lebih dari {quantity} untuk mendapat {quantity} gratis",
+	"coupon_minimum_spend":        "minimum belanja untuk kupon ini adalah {amount}",
+	"coupon_not_applicable":       "kupon tidak berlaku untuk {target} ini",
+	"coupon_not_found":            "kupon tidak ditemukan",
+	"coupon_not_started":          "kupon belum berlaku",
+	"coupon_usage_limit_reached":  "batas pemakaian kupon sudah tercapai",
+	"duplicate_currency":          "mata uang {currency} tercantum lebih dari sekali",
+	"email_exists":                "email sudah terdaftar",
+	"empty_import_file":           "berkas impor kosong",
+	"image_dimensions_too_large":  "dimensi gambar tidak boleh lebih dari {max_width}x{max_height} piksel atau {max_pixels} piksel secara total",
+	"image_not_found":             "gambar tidak ditemukan",
+	"image_required":              "berkas gambar wajib diisi",
+	"image_save_failed":           "gagal menyimpan gambar",
+	"image_store_failed":          "gagal menyimpan gambar",
+	"image_too_large":             "ukuran gambar tidak boleh lebih dari 5 MB",
+	"incorrect_password":          "kata sandi salah",
+	"insufficient_balance":        "saldo tidak mencukupi, total harga: {total}, saldo Anda: {balance}",
+	"insufficient_stock":          "stok tidak mencukupi, hanya tersisa {available}",
+	"internal_error":              "kesalahan server internal",
+	"invalid_balance":             "saldo tidak valid",
+	"invalid_body":                "isi permintaan bukan JSON yang valid",
+	"invalid_coupon_limits":       "batas kupon tidak boleh negatif",
+	"invalid_coupon_percentage":   "persentase harus antara 1 dan 100",
+	"invalid_coupon_period":       "kupon harus berakhir setelah dimulai",
+	"invalid_coupon_type":         "jenis kupon tidak valid",
+	"invalid_coupon_value":        "nilai kupon harus lebih dari nol",
+	"invalid_credentials":         "email atau kata sandi salah",
+	"invalid_delete_mode":         "mode harus salah satu dari restrict, cascade atau reassign",
+	"invalid_email":               "alamat email tidak valid",
+	"invalid_fields":              "isian tidak valid",
+	"invalid_format":              "format harus salah satu dari {formats}",
+	"invalid_image":               "gambar tidak dapat dibaca",
+	"invalid_image_order":         "image_ids harus memuat setiap gambar produk tepat satu kali",
+	"invalid_import_format":       "format harus csv atau json",
+	"invalid_job_name":            "nama hanya boleh berisi huruf kecil, angka, tanda hubung dan garis bawah",
+	"invalid_metrics_token":       "token metrik tidak valid",
+	"invalid_movement_type":       "jenis pergerakan stok tidak valid",
+	"invalid_period_days":         "period_days tidak boleh negatif",
+	"invalid_postal_code":         "kode pos harus 5 digit",
+	"invalid_price":               "harga tidak valid",
+	"invalid_price_change_end":    "perubahan harga harus berakhir setelah dimulai",
+	"invalid_price_change_start":  "perubahan harga harus dimulai di masa mendatang",
+	"invalid_product":             "data produk tidak valid",
+	"invalid_product_id":          "ID produk tidak valid",
+	"invalid_quantity":            "jumlah harus lebih dari nol",
+	"invalid_report":              "laporan tidak valid",
+	"invalid_request":             "permintaan tidak valid",
+	"invalid_reservation":         "data reservasi tidak valid",
+	"invalid_signature":           "tanda tangan tidak valid atau sudah kedaluwarsa",
+	"invalid_target_category":     "target_category_id yang berbeda diperlukan untuk memindahkan produk",
+	"invalid_tax_rate":            "tarif pajak harus antara 0 dan 10000 (100%)",
+	"invalid_token":               "token tidak valid",
+	"invalid_token_claims":        "gagal membaca klaim dari token",
+	"invalid_transaction":         "data transaksi tidak valid",
+	"invalid_transaction_filter":  "filter transaksi tidak valid",
+	"invalid_user_id":             "ID pengguna tidak valid",
+	"invalid_variant":             "data varian tidak valid",
+	"invalid_weight":              "berat tidak boleh negatif",
+	"job_create_failed":           "gagal membuat tugas",
+	"job_disabled":                "tugas dinonaktifkan",
+	"job_name_exists":             "nama tugas sudah ada",
+	"job_not_found":               "tugas tidak ditemukan",
+	"missing_import_column":       "kolom {column} tidak ada",
+	"missing_user_email":          "email pengguna tidak ditemukan dalam konteks",
+	"missing_user_id":             "ID pengguna tidak ditemukan dalam konteks",
+	"negative_stock":              "stok tidak boleh kurang dari nol",
+	"password_hash_failed":        "gagal mengenkripsi kata sandi",
+	"price_change_overlap":        "perubahan harga bertumpang tindih dengan perubahan terjadwal lain",
+	"price_change_started":        "perubahan harga sudah dimulai",
+	"product_create_failed":       "gagal membuat produk",
+	"product_in_stock":            "produk masih tersedia",
+	"product_not_found":           "produk tidak ditemukan",
+	"products_not_found":          "produk tidak ditemukan",
+	"reservation_expired":         "reservasi sudah kedaluwarsa",
+	"reservation_inactive":        "reservasi sudah tidak aktif",
+	"reservation_mismatch":        "reservasi tidak sesuai dengan pembelian",
+	"reservation_not_found":       "reservasi tidak ditemukan",
+	"rupiah_price_not_allowed":    "harga rupiah diatur pada produk itu sendiri",
+	"sale_adjustment_not_allowed": "penjualan dicatat melalui pembelian",
+	"scheduled_change_not_found":  "perubahan terjadwal tidak ditemukan",
+	"shipping_address_required":   "alamat pengiriman wajib diisi, tambahkan ke buku alamat Anda",
+	"shipping_unavailable":        "tidak ada pengiriman ke {province}",
+	"sku_exists":                  "SKU sudah ada",
+	"target_category_not_found":   "kategori tujuan tidak ditemukan",
+	"thumbnail_failed":            "gagal membuat gambar mini",
+	"thumbnail_store_failed":      "gagal menyimpan gambar mini",
+	"timeout":                     "permintaan memakan waktu terlalu lama",
+	"title_exists":                "judul sudah ada",
+	"token_generation_failed":     "gagal membuat token",
+	"transaction_not_found":       "transaksi tidak ditemukan",
+	"unknown_category":            "category_id tidak merujuk ke kategori yang ada",
+	"unknown_job_type":            "jenis tugas {type} tidak dikenal",
+	"unsupported_image_type":      "hanya gambar JPEG, PNG dan GIF yang diperbolehkan",
+	"user_create_failed":          "gagal membuat pengguna",
+	"user_not_found":              "pengguna tidak ditemukan",
+	"variant_create_failed":       "gagal membuat varian",
+	"variant_not_found":           "varian tidak ditemukan",
+	"variant_options_required":    "minimal satu ukuran atau warna wajib diisi",
+	"variant_required":            "produk ini memiliki varian, variant_id wajib diisi",
+	"weak_password":               "kata sandi terlalu lemah",
+
+	"field.balance":    "{field} harus antara {min} dan {max}",
+	"field.before":     "{field} harus sebelum {other}",
+	"field.email":      "{field} harus berupa alamat email yang valid",
+	"field.exists":     "{field} tidak merujuk ke data yang ada",
+	"field.gt":         "{field} harus lebih dari {value}",
+	"field.invalid":    "{field} tidak valid",
+	"field.ltefield":   "{field} tidak boleh lebih dari {other}",
+	"field.max":        "{field} paling banyak {max}",
+	"field.max_length": "{field} paling banyak {max} karakter",
+	"field.min":        "{field} paling sedikit {min}",
+	"field.min_length": "{field} paling sedikit {min} karakter",
+	"field.oneof":      "{field} harus salah satu dari {values}",
+	"field.password":   "{field} harus {min} sampai {max} karakter dan memadukan paling sedikit dua dari huruf kecil, huruf besar, angka dan simbol",
+	"field.price":      "{field} harus antara {min} dan {max}",
+	"field.required":   "{field} wajib diisi",
+
+	"term.category":       "kategori",
+	"term.city":           "kota",
+	"term.phone":          "telepon",
+	"term.postal code":    "kode pos",
+	"term.product":        "produk",
+	"term.province":       "provinsi",
+	"term.recipient name": "nama penerima",
+	"term.street":         "jalan",
+
+	"address_deleted":            "Alamat berhasil dihapus",
+	"back_in_stock_unsubscribed": "Anda tidak akan lagi diberi tahu saat produk ini tersedia kembali",
+	"balance_updated":            "Saldo Anda berhasil diperbarui menjadi {balance}",
+	"category_deleted":           "Kategori berhasil dihapus",
+	"coupon_deleted":             "Kupon berhasil dihapus",
+	"gallery_reordered":          "Urutan galeri berhasil diperbarui",
+	"image_deleted":              "Gambar berhasil dihapus",
+	"job_deleted":                "Tugas berhasil dihapus",
+	"price_change_cancelled":     "Perubahan harga telah dibatalkan",
+	"product_deleted":            "Produk berhasil dihapus",
+	"product_purchased":          "Anda berhasil membeli produk",
+	"reservation_released":       "Reservasi berhasil dilepaskan",
+	"variant_deleted":            "Varian berhasil dihapus",
+
+	"notification.back_in_stock.message": "Halo {name}, {title} sudah tersedia kembali.",
+	"notification.back_in_stock.subject": "{title} tersedia kembali",
+	"notification.low_stock.message":     "Stok {title} tinggal {stock}, sudah mencapai batas pemesanan ulang {threshold}.",
+	"notification.low_stock.subject":     "Stok menipis: {title}",
+	"notification.out_of_stock.message":  "Stok {title} habis.",
+	"notification.out_of_stock.subject":  "Stok habis: {title}",
+}
@@ -0,0 +1,149 @@
+// Package i18n holds the Indonesian and English message catalogs, picks the
+// language of each request from Accept-Language and renders messages with
+// the number and currency format of that language.
+package i18n
+
+import (
+	"context"
+	"e-commerce/money"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Supported locales.
+const (
+	English    = "en"
+	Indonesian = "id"
+)
+
+// Params fill the {name} placeholders of a message.
+type Params map[string]any
+
+// Term is a word that is translated before it is put into a message, e.g.
+// Term("product") becomes "produk" in Indonesian.
+type Term string
+
+var catalogs = map[string]map[string]string{
+	English:    english,
+	Indonesian: indonesian,
+}
+
+// Supported reports whether there is a catalog for locale.
+func Supported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Negotiate picks the supported locale the client prefers most in an
+// Accept-Language header, e.g. "id-ID,id;q=0.9,en;q=0.8". Regions are
+// ignored. fallback is used when nothing matches.
+func Negotiate(header, fallback string) string {
+	best, bestQuality := fallback, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !Supported(language) {
+			continue
+		}
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+		if quality > bestQuality {
+			best, bestQuality = language, quality
+		}
+	}
+	return best
+}
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext returns the locale stored in ctx, or English when there is
+// none, as in background jobs.
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+	return English
+}
+
+// Has reports whether the English catalog, which every other catalog
+// mirrors, has key.
+func Has(key string) bool {
+	_, ok := english[key]
+	return ok
+}
+
+// Translate renders the message for key in locale, falling back to the
+// English catalog. It reports false when no catalog has key or params lack
+// one of its placeholders.
+func Translate(locale, key string, params map[string]any) (string, bool) {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		if message, ok = english[key]; !ok {
+			return "", false
+		}
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(message, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(message[start:], '}')
+		if end < 0 {
+			break
+		}
+		value, ok := params[message[start+1:start+end]]
+		if !ok {
+			return "", false
+		}
+		b.WriteString(message[:start])
+		b.WriteString(Format(locale, value))
+		message = message[start+end+1:]
+	}
+	b.WriteString(message)
+	return b.String(), true
+}
+
+// T renders the message for key in the locale of ctx. It returns key itself
+// when the message can't be rendered.
+func T(ctx context.Context, key string, params Params) string {
+	if message, ok := Translate(FromContext(ctx), key, params); ok {
+		return message
+	}
+	return key
+}
+
+// Format writes a message parameter for readers of locale. Numbers get
+// thousands separators, money its currency symbol and terms are
+// translated.
+func Format(locale string, value any) string {
+	moneyLocale := money.LookupLocale(locale)
+	switch v := value.(type) {
+	case Term:
+		if term, ok := Translate(locale, "term."+string(v), nil); ok {
+			return term
+		}
+		return string(v)
+	case money.Money:
+		return money.Format(v, moneyLocale)
+	case int:
+		return money.FormatNumber(int64(v), moneyLocale)
+	case int64:
+		return money.FormatNumber(v, moneyLocale)
+	default:
+		return fmt.Sprint(v)
+	}
+}
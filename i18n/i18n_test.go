@@ -0,0 +1,149 @@
+package i18n
+
+import (
+	"context"
+	"e-commerce/money"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		header, want string
+	}{
+		{"", English},
+		{"id", Indonesian},
+		{"id-ID,id;q=0.9,en-US;q=0.8,en;q=0.7", Indonesian},
+		{"en-GB,en;q=0.9,id;q=0.8", English},
+		{"fr-FR,fr;q=0.9,id;q=0.5", Indonesian},
+		{"en;q=0.2, ID;q=0.8", Indonesian},
+		{"id;q=0, en;q=0.1", English},
+		{"fr, de;q=0.9", English},
+		{"*", English},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, Negotiate(tc.header, English), tc.header)
+	}
+	assert.Equal(t, Indonesian, Negotiate("fr", Indonesian))
+}
+
+func TestTranslate(t *testing.T) {
+	message, ok := Translate(Indonesian, "insufficient_balance", Params{"total": money.IDR(1500000), "balance": money.IDR(20000)})
+	assert.True(t, ok)
+	assert.Equal(t, "saldo tidak mencukupi, total harga: Rp1.500.000, saldo Anda: Rp20.000", message)
+
+	message, ok = Translate(English, "coupon_not_applicable", Params{"target": Term("category")})
+	assert.True(t, ok)
+	assert.Equal(t, "coupon does not apply to this category", message)
+	message, _ = Translate(Indonesian, "coupon_not_applicable", Params{"target": Term("category")})
+	assert.Equal(t, "kupon tidak berlaku untuk kategori ini", message)
+
+	message, _ = Translate(Indonesian, "field.price", Params{"field": "price", "min": 1, "max": 50000000})
+	assert.Equal(t, "price harus antara 1 dan 50.000.000", message)
+
+	message, _ = Translate("fr", "product_not_found", nil)
+	assert.Equal(t, "product not found", message)
+
+	_, ok = Translate(English, "insufficient_stock", nil)
+	assert.False(t, ok, "placeholders without params")
+	_, ok = Translate(English, "no_such_key", nil)
+	assert.False(t, ok)
+}
+
+func TestT(t *testing.T) {
+	ctx := WithLocale(context.Background(), Indonesian)
+	assert.Equal(t, "Saldo Anda berhasil diperbarui menjadi Rp150.000", T(ctx, "balance_updated", Params{"balance": money.IDR(150000)}))
+	assert.Equal(t, "Product has been successfully deleted", T(context.Background(), "product_deleted", nil))
+	assert.Equal(t, "no_such_key", T(ctx, "no_such_key", nil))
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "1.234.567", Format(Indonesian, 1234567))
+	assert.Equal(t, "1,234,567", Format(English, int64(1234567)))
+	assert.Equal(t, "$10.50", Format(English, money.New(1050, "USD")))
+	assert.Equal(t, "kode pos", Format(Indonesian, Term("postal code")))
+	assert.Equal(t, "unlisted", Format(Indonesian, Term("unlisted")))
+	assert.Equal(t, "XL", Format(Indonesian, "XL"))
+}
+
+var placeholder = regexp.MustCompile(`\{\w+\}`)
+
+func TestCatalogsMatch(t *testing.T) {
+	for key, message := range english {
+		translated, ok := indonesian[key]
+		if !assert.True(t, ok, "id catalog is missing %q", key) {
+			continue
+		}
+		want := placeholder.FindAllString(message, -1)
+		got := placeholder.FindAllString(translated, -1)
+		slices.Sort(want)
+		slices.Sort(got)
+		assert.Equal(t, want, got, "placeholders of %q", key)
+	}
+	for key := range indonesian {
+		assert.Contains(t, english, key, "en catalog is missing %q", key)
+	}
+}
+
+var errorCode = regexp.MustCompile(`apperror\.(?:Validation|Unauthorized|Forbidden|NotFound|Conflict|Internal|New)\([^"\n]*"([a-z_]+)"`)
+
+// TestCatalogsCoverErrors checks that every error code used in the code
+// base has a message.
+func TestCatalogsCoverErrors(t *testing.T) {
+	var codes []string
+	err := filepath.WalkDir("..", func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == "docs" || strings.HasPrefix(d.Name(), ".")) && path != ".." {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range errorCode.FindAllSubmatch(source, -1) {
+			codes = append(codes, string(match[1]))
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, codes)
+	for _, code := range codes {
+		assert.True(t, Has(code), "no message for error code %q", code)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware(English))
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, T(c.Request.Context(), "product_deleted", nil))
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "id-ID")
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, "Produk berhasil dihapus", rec.Body.String())
+	assert.Equal(t, Indonesian, rec.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", rec.Header().Get("Vary"))
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "Product has been successfully deleted", rec.Body.String())
+	assert.Equal(t, English, rec.Header().Get("Content-Language"))
+}
@@ -0,0 +1,17 @@
+package i18n
+
+import "github.com/gin-gonic/gin"
+
+// Middleware negotiates the locale of each request from Accept-Language,
+// using fallback when the client accepts none of the supported ones, and
+// stores it in the request context. The response says which language it
+// is in.
+func Middleware(fallback string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := Negotiate(c.GetHeader("Accept-Language"), fallback)
+		c.Request = c.Request.WithContext(WithLocale(c.Request.Context(), locale))
+		c.Header("Content-Language", locale)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
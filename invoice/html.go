@@ -0,0 +1,20 @@
+package invoice
+
+import (
+	"e-commerce/helpers"
+	"embed"
+	"html/template"
+	"io"
+)
+
+//go:embed templates/invoice.html
+var templates embed.FS
+
+var htmlTemplate = template.Must(template.New("invoice.html").
+	Funcs(template.FuncMap{"rupiah": helpers.FormatRupiah}).
+	ParseFS(templates, "templates/invoice.html"))
+
+// RenderHTML writes inv as an HTML page.
+func RenderHTML(w io.Writer, inv Invoice) error {
+	return htmlTemplate.Execute(w, inv)
+}
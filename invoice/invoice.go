@@ -0,0 +1,66 @@
+// Package invoice renders purchase invoices as HTML and PDF.
+package invoice
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Party is the seller or the buyer on an invoice. TaxID is the NPWP.
+type Party struct {
+	Name    string
+	Email   string
+	Phone   string
+	Address []string
+	TaxID   string
+}
+
+// Line is one item on an invoice. Amounts are in whole rupiah.
+type Line struct {
+	Description string
+	SKU         string
+	Quantity    int
+	UnitPrice   int
+	Amount      int
+}
+
+type Invoice struct {
+	Number      string
+	IssuedAt    time.Time
+	Seller      Party
+	Buyer       Party
+	Lines       []Line
+	Subtotal    int
+	Discount    int
+	CouponCode  string
+	TaxName     string
+	TaxRate     int
+	Tax         int
+	TaxIncluded bool
+	Shipping    int
+	Total       int
+}
+
+// Number formats the n-th invoice of a year, e.g. INV/2024/000042.
+func Number(year, n int) string {
+	return fmt.Sprintf("INV/%d/%06d", year, n)
+}
+
+// TaxLabel describes the tax line, e.g. "PPN 11%" or "PPN 11% (included)".
+func (inv Invoice) TaxLabel() string {
+	rate := fmt.Sprintf("%d", inv.TaxRate/100)
+	if inv.TaxRate%100 != 0 {
+		rate = strings.TrimRight(fmt.Sprintf("%d.%02d", inv.TaxRate/100, inv.TaxRate%100), "0")
+	}
+	label := fmt.Sprintf("%s %s%%", inv.TaxName, rate)
+	if inv.TaxIncluded {
+		label += " (included)"
+	}
+	return strings.TrimSpace(label)
+}
+
+// Filename is a file name for the invoice without an extension.
+func (inv Invoice) Filename() string {
+	return strings.ReplaceAll(inv.Number, "/", "-")
+}
@@ -0,0 +1,97 @@
+package invoice
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleInvoice() Invoice {
+	return Invoice{
+		Number:   Number(2024, 42),
+		IssuedAt: time.Date(2024, 7, 17, 9, 30, 0, 0, time.UTC),
+		Seller:   Party{Name: "Toko Maju", Address: []string{"Jl. Asia Afrika 8", "Bandung"}, TaxID: "01.234.567.8-901.000"},
+		Buyer:    Party{Name: "Budi (Kantor)", Email: "budi@example.com", Address: []string{"Jl. Merdeka 1", "Jakarta 10110"}},
+		Lines: []Line{
+			{Description: "Kemeja Batik", SKU: "KB-M-BLUE", Quantity: 2, UnitPrice: 150000, Amount: 300000},
+		},
+		Subtotal: 300000,
+		Discount: 30000,
+		TaxName:  "PPN",
+		TaxRate:  1100,
+		Tax:      29700,
+		Shipping: 15000,
+		Total:    314700,
+	}
+}
+
+func TestNumber(t *testing.T) {
+	assert.Equal(t, "INV/2024/000042", Number(2024, 42))
+	assert.Equal(t, "INV-2024-000042", sampleInvoice().Filename())
+}
+
+func TestTaxLabel(t *testing.T) {
+	inv := sampleInvoice()
+	assert.Equal(t, "PPN 11%", inv.TaxLabel())
+
+	inv.TaxRate, inv.TaxIncluded = 550, true
+	assert.Equal(t, "PPN 5.5% (included)", inv.TaxLabel())
+}
+
+func TestRenderHTML(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.NoError(t, RenderHTML(&buf, sampleInvoice()))
+
+	html := buf.String()
+	assert.Contains(t, html, "INV/2024/000042")
+	assert.Contains(t, html, "Budi (Kantor)")
+	assert.Contains(t, html, "Rp150.000")
+	assert.Contains(t, html, "-Rp30.000")
+	assert.Contains(t, html, "PPN 11%")
+	assert.Contains(t, html, "Rp314.700")
+}
+
+func TestRenderPDF(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.NoError(t, RenderPDF(&buf, sampleInvoice()))
+
+	pdf := buf.String()
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, "(No. INV/2024/000042)")
+	assert.Contains(t, pdf, `(Budi \(Kantor\))`)
+	assert.Contains(t, pdf, "(Rp314.700)")
+
+	// Every object offset in the xref table must point at that object
+	xref, err := strconv.Atoi(regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(pdf)[1])
+	assert.NoError(t, err)
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(pdf[xref:], -1)
+	assert.Len(t, entries, 6)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		assert.True(t, strings.HasPrefix(pdf[offset:], strconv.Itoa(i+1)+" 0 obj"), "object %d", i+1)
+	}
+}
+
+func TestRenderPDFPaginates(t *testing.T) {
+	inv := sampleInvoice()
+	for i := 0; i < 60; i++ {
+		inv.Lines = append(inv.Lines, Line{Description: "Item", Quantity: 1, UnitPrice: 1000, Amount: 1000})
+	}
+	var buf bytes.Buffer
+
+	assert.NoError(t, RenderPDF(&buf, inv))
+
+	assert.Contains(t, buf.String(), "/Count 2")
+}
+
+func TestPDFString(t *testing.T) {
+	assert.Equal(t, `Caf\351 \(1\) \\ ?`, pdfString("Café (1) \\ 日"))
+}
@@ -0,0 +1,223 @@
+package invoice
+
+import (
+	"bytes"
+	"e-commerce/helpers"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A4 in points.
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 50
+)
+
+// Right edges of the numeric columns and left edges of the text columns.
+const (
+	colItem      = margin
+	colSKU       = 260
+	colQuantity  = 370
+	colUnitPrice = 460
+	colAmount    = pageWidth - margin
+)
+
+// RenderPDF writes inv as a PDF using the built-in Helvetica fonts, so no
+// font files are needed. Long invoices continue on further pages.
+func RenderPDF(w io.Writer, inv Invoice) error {
+	doc := &pdfDocument{}
+	page := doc.addPage()
+
+	page.text(margin, 790, 20, true, "INVOICE")
+	page.textRight(colAmount, 795, 10, false, "No. "+inv.Number)
+	page.textRight(colAmount, 780, 10, false, "Date: "+inv.IssuedAt.Format("02 Jan 2006"))
+
+	y := page.party(margin, 740, "Seller", inv.Seller, "NPWP: ")
+	y = min(y, page.party(320, 740, "Bill to", inv.Buyer, ""))
+
+	y -= 20
+	header := func(page *pdfPage, y float64) {
+		page.text(colItem, y, 10, true, "Item")
+		page.text(colSKU, y, 10, true, "SKU")
+		page.textRight(colQuantity, y, 10, true, "Qty")
+		page.textRight(colUnitPrice, y, 10, true, "Unit price")
+		page.textRight(colAmount, y, 10, true, "Amount")
+		page.line(margin, y-6, colAmount, y-6)
+	}
+	header(page, y)
+	for _, line := range inv.Lines {
+		y -= 18
+		if y < margin+120 {
+			page = doc.addPage()
+			y = pageHeight - margin
+			header(page, y)
+			y -= 18
+		}
+		page.text(colItem, y, 10, false, truncate(line.Description, 38))
+		page.text(colSKU, y, 10, false, truncate(line.SKU, 16))
+		page.textRight(colQuantity, y, 10, false, strconv.Itoa(line.Quantity))
+		page.textRight(colUnitPrice, y, 10, false, helpers.FormatRupiah(line.UnitPrice))
+		page.textRight(colAmount, y, 10, false, helpers.FormatRupiah(line.Amount))
+	}
+	y -= 10
+	page.line(margin, y, colAmount, y)
+
+	total := func(label, amount string, bold bool) {
+		y -= 16
+		page.textRight(colUnitPrice, y, 10, bold, label)
+		page.textRight(colAmount, y, 10, bold, amount)
+	}
+	total("Subtotal", helpers.FormatRupiah(inv.Subtotal), false)
+	if inv.Discount != 0 {
+		label := "Discount"
+		if inv.CouponCode != "" {
+			label += " (" + inv.CouponCode + ")"
+		}
+		total(label, "-"+helpers.FormatRupiah(inv.Discount), false)
+	}
+	total(inv.TaxLabel(), helpers.FormatRupiah(inv.Tax), false)
+	total("Shipping", helpers.FormatRupiah(inv.Shipping), false)
+	y -= 4
+	page.line(colQuantity, y, colAmount, y)
+	total("Total", helpers.FormatRupiah(inv.Total), true)
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// party writes a block of seller or buyer details and returns the y of its
+// last line.
+func (p *pdfPage) party(x, y float64, title string, party Party, taxIDLabel string) float64 {
+	p.text(x, y, 10, true, title)
+	lines := append([]string{party.Name}, party.Address...)
+	for _, extra := range []string{party.Phone, party.Email} {
+		if extra != "" {
+			lines = append(lines, extra)
+		}
+	}
+	if party.TaxID != "" {
+		lines = append(lines, taxIDLabel+party.TaxID)
+	}
+	for _, line := range lines {
+		y -= 14
+		p.text(x, y, 10, false, truncate(line, 45))
+	}
+	return y
+}
+
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+func (p *pdfPage) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %g Tf %g %g Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+func (p *pdfPage) textRight(right, y, size float64, bold bool, s string) {
+	p.text(right-textWidth(s, size), y, size, bold, s)
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %g %g m %g %g l S\n", x1, y1, x2, y2)
+}
+
+type pdfDocument struct {
+	pages []*pdfPage
+}
+
+func (d *pdfDocument) addPage() *pdfPage {
+	page := &pdfPage{}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// WriteTo writes the catalog, the page tree, the two fonts and every page
+// with its content stream, followed by the cross-reference table.
+func (d *pdfDocument) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.WriteTo(w)
+}
+
+// pdfString encodes s for WinAnsiEncoding and escapes it for a PDF string
+// literal. Characters outside Latin-1 become "?".
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '€':
+			b.WriteString(`\200`)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, `\%03o`, r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// Helvetica glyph widths in thousandths of the font size, for the
+// characters in amounts. Other characters use an average width.
+var helveticaWidths = map[rune]float64{
+	' ': 278, '.': 278, ',': 278, '-': 333, '%': 889, '(': 333, ')': 333, '/': 278,
+	'0': 556, '1': 556, '2': 556, '3': 556, '4': 556, '5': 556, '6': 556, '7': 556, '8': 556, '9': 556,
+	'R': 722, 'p': 556, 'i': 222, 'l': 222, 't': 278, 'f': 278, 'r': 333, 'I': 278, 'j': 222,
+}
+
+func textWidth(s string, size float64) float64 {
+	width := 0.0
+	for _, r := range s {
+		w, ok := helveticaWidths[r]
+		if !ok {
+			w = 556
+		}
+		width += w
+	}
+	return width * size / 1000
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	logger.Info("login", "email", "buyer@example.com", "password", "hunter2", slog.Group("headers", "Authorization", "Bearer abc"), "refresh_token", "xyz")
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "buyer@example.com", line["email"])
+	assert.Equal(t, Redacted, line["password"])
+	assert.Equal(t, Redacted, line["refresh_token"])
+	assert.Equal(t, map[string]interface{}{"Authorization": Redacted}, line["headers"])
+}
+
+func TestParseLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelDebug, ParseLevel("debug"))
+	assert.Equal(t, slog.LevelWarn, ParseLevel("WARN"))
+	assert.Equal(t, slog.LevelInfo, ParseLevel(""))
+	assert.Equal(t, slog.LevelInfo, ParseLevel("verbose"))
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	router := gin.New()
+	router.Use(Middleware(New(&buf, slog.LevelInfo)))
+	router.GET("/orders/:id", func(c *gin.Context) {
+		SetUser(c, 7, "admin")
+		Request(c).Info("loading order")
+		c.Status(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, "abc-123", rec.Header().Get(RequestIDHeader))
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var handlerLine, requestLine map[string]interface{}
+	assert.NoError(t, json.Unmarshal(lines[0], &handlerLine))
+	assert.NoError(t, json.Unmarshal(lines[1], &requestLine))
+	assert.Equal(t, "abc-123", handlerLine["request_id"])
+	assert.Equal(t, float64(7), handlerLine["user_id"])
+	assert.Equal(t, "WARN", requestLine["level"])
+	assert.Equal(t, "/orders/:id", requestLine["route"])
+	assert.Equal(t, float64(404), requestLine["status"])
+	assert.Equal(t, "admin", requestLine["role"])
+}
+
+func TestMiddlewareGeneratesRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware(New(&bytes.Buffer{}, slog.LevelInfo)))
+	router.GET("/", func(c *gin.Context) {})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "not a valid id\n")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Regexp(t, "^[0-9a-f]{32}$", rec.Header().Get(RequestIDHeader))
+}
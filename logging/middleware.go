@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID from clients and proxies, and back
+// in the response.
+const RequestIDHeader = "X-Request-ID"
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// Middleware gives every request an ID, taken from X-Request-ID when the
+// client sent a usable one, and a logger carrying it. When the request is
+// done it logs the method, path, status and latency.
+func Middleware(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), base.With("request_id", requestID)))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+		Request(c).LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// Request returns the logger of the request handled by c.
+func Request(c *gin.Context) *slog.Logger {
+	return FromContext(c.Request.Context())
+}
+
+// SetUser adds the authenticated user to the request's logger.
+func SetUser(c *gin.Context, userID uint, role string) {
+	logger := Request(c).With("user_id", userID, "role", role)
+	c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), logger))
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
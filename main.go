@@ -1,43 +1,126 @@
 package main
 
 import (
-	"e-commerce/auth"
+	"context"
 	"e-commerce/config"
-	_ "e-commerce/docs"
+	"e-commerce/entity"
 	"e-commerce/handlers"
 	"e-commerce/helpers"
 	"e-commerce/models"
-	"fmt"
+	"e-commerce/repository"
+	"e-commerce/router"
+	"e-commerce/services"
+	"e-commerce/tracing"
+	"e-commerce/validation"
+	"errors"
 	"log"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
 
 	"gorm.io/gorm"
 
-	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	_ "github.com/lib/pq"
-	swaggerfiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 func main() {
+	logger := config.NewLogger()
+	slog.SetDefault(logger)
+	shutdownTracing, err := tracing.Setup(context.Background(), config.TracingConfig())
+	if err != nil {
+		log.Fatal("Error setting up tracing ", err)
+	}
 	db := config.ConnectDatabase()
-	r := gin.Default()
+	store := config.NewBlobStore()
+	signer := config.NewURLSigner()
+	rates := config.NewRateProvider()
+	reservationService := services.ReservationService{ReservationRepository: repository.NewReservationRepo(db)}
+	stopSweeper := reservationService.StartSweeper(config.ReservationSweepInterval())
+	stockAlertService := services.StockAlertService{
+		Repository:     repository.NewStockAlertRepo(db),
+		Notifier:       config.NewNotifier(),
+		AdminRecipient: config.StockAlertRecipient(),
+	}
+	stopDispatcher := stockAlertService.StartDispatcher(config.StockAlertInterval())
+	priceScheduleService := services.PriceScheduleService{Repository: repository.NewPriceScheduleRepo(db)}
+	stopScheduler := priceScheduleService.StartScheduler(config.PriceScheduleInterval())
+	exportStore := config.NewExportStore()
+	mailer := config.NewMailer()
+	jobService := services.JobService{
+		Repository: repository.NewJobRepo(db),
+		Runners: map[string]services.JobRunner{
+			entity.JobSalesReport: services.SalesReportJob{
+				Reports: services.ReportService{ReportRepository: repository.NewReportRepo(db)},
+				Store:   exportStore,
+				Mailer:  mailer,
+			},
+			entity.JobTransactionsExport: services.TransactionsExportJob{
+				Transactions: services.TransactionService{TransactionRepository: repository.NewTransactionRepo(db)},
+				Store:        exportStore,
+				Mailer:       mailer,
+			},
+		},
+		Instance: config.JobInstanceID(),
+	}
+	stopJobs := jobService.StartScheduler(config.JobSchedulerInterval())
+	readiness := &handlers.Readiness{}
+	binding.Validator = validation.Binding{}
 	insertSampleDataGorm(db)
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
-	r.POST("/users/register", handlers.Register(db))
-	r.POST("/users/login", handlers.Login(db))
-	r.PATCH("/users/topup", auth.AuthenticationMiddleware(), handlers.UpdateUserBalance(db))
-	r.GET("/categories", auth.AuthorizationMiddleware(), handlers.GetCategories(db))
-	r.POST("/categories", auth.AuthorizationMiddleware(), handlers.CreateCategory(db))
-	r.PATCH("/categories/:categoryId", auth.AuthorizationMiddleware(), handlers.UpdateCategory(db))
-	r.DELETE("/categories/:categoryId", auth.AuthorizationMiddleware(), handlers.DeleteCategory(db))
-	r.GET("/products", auth.AuthenticationMiddleware(), handlers.GetProducts(db))
-	r.POST("/products", auth.AuthorizationMiddleware(), handlers.CreateProduct(db))
-	r.PUT("/products/:productId", auth.AuthorizationMiddleware(), handlers.UpdateProduct(db))
-	r.DELETE("/products/:productId", auth.AuthorizationMiddleware(), handlers.DeleteProduct(db))
-	r.POST("/transactions", auth.AuthenticationMiddleware(), handlers.CreateTransaction(db))
-	r.GET("/transactions/my-transactions", auth.AuthenticationMiddleware(), handlers.GetMyTransaction(db))
-	r.GET("/transactions/user-transactions", auth.AuthorizationMiddleware(), handlers.GetTransaction(db))
-	r.Run()
+	r := router.New(router.Dependencies{
+		DB:             db,
+		Logger:         logger,
+		Readiness:      readiness,
+		Models:         config.Models,
+		Store:          store,
+		Signer:         signer,
+		ImageLimits:    config.ImageLimits(),
+		Rates:          rates,
+		Jobs:           jobService,
+		Tax:            config.TaxConfig(),
+		Shipping:       config.NewShippingCalculator(),
+		InvoiceSeller:  config.InvoiceSeller(),
+		ReservationTTL: config.ReservationTTL(),
+		MetricsToken:   config.MetricsToken(),
+		DefaultLocale:  config.DefaultLocale(),
+		RequestTimeout: config.RequestTimeout(),
+	}, config.APIOptions())
+
+	srv := config.NewHTTPServer(r)
+	go func() {
+		slog.Info("server listening", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Error starting server ", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	slog.Info("shutting down")
+
+	// Fail readiness first so no new purchases are routed here, then let
+	// in-flight requests finish before the workers and the pool go away
+	readiness.Drain()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout())
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("draining requests failed", "error", err)
+	}
+	stopSweeper()
+	stopDispatcher()
+	stopScheduler()
+	stopJobs()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("flushing traces failed", "error", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			slog.Error("closing database failed", "error", err)
+		}
+	}
+	slog.Info("server stopped")
 }
 func insertSampleDataGorm(db *gorm.DB) {
 	sampleUsers := []models.User{
@@ -63,7 +146,7 @@ func insertSampleDataGorm(db *gorm.DB) {
 			log.Fatalf("Error creating user: %v", result.Error)
 		}
 
-		fmt.Println("User created successfully:", newUser.ID)
+		slog.Info("sample user created", "user_id", newUser.ID)
 	}
-	fmt.Println("Insert data success")
+	slog.Info("sample data inserted")
 }
@@ -0,0 +1,127 @@
+// Package metrics exposes Prometheus metrics for HTTP requests, the
+// database and business events.
+package metrics
+
+import (
+	"crypto/subtle"
+	"e-commerce/apperror"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+)
+
+const namespace = "ecommerce"
+
+// Registry holds every metric of the service, plus the Go runtime and
+// process collectors.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of HTTP requests by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of database queries by table and operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"table", "operation"})
+
+	// Purchases counts completed purchases.
+	Purchases = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "purchases_total",
+		Help:      "Completed purchases.",
+	})
+
+	// Revenue sums the total price of completed purchases, in rupiah.
+	Revenue = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "revenue_rupiah_total",
+		Help:      "Total price of completed purchases in rupiah.",
+	})
+
+	// TopUps counts balance top-ups.
+	TopUps = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "topups_total",
+		Help:      "Balance top-ups.",
+	})
+
+	// FailedLogins counts logins rejected for a wrong email or password.
+	FailedLogins = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "failed_logins_total",
+		Help:      "Logins rejected for a wrong email or password.",
+	})
+
+	// OutOfStockRejections counts purchases and reservations rejected for
+	// lack of stock.
+	OutOfStockRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "out_of_stock_rejections_total",
+		Help:      "Purchases and reservations rejected for lack of stock.",
+	}, []string{"operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		dbQueryDuration,
+		Purchases,
+		Revenue,
+		TopUps,
+		FailedLogins,
+		OutOfStockRejections,
+	)
+}
+
+// Middleware records the duration of every request by route template, so
+// /products/1 and /products/2 share a series. Requests matching no route
+// are recorded as "unmatched".
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpRequestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format. When token is
+// not empty, requests must send it as a bearer token.
+func Handler(token string) gin.HandlerFunc {
+	handler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	return func(c *gin.Context) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+			apperror.Respond(c, apperror.Unauthorized("invalid_metrics_token", "Invalid metrics token"))
+			return
+		}
+		handler.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// RegisterDB exports the connection pool statistics of db.
+func RegisterDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return Registry.Register(collectors.NewDBStatsCollector(sqlDB, "postgres"))
+}
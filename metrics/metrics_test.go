@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func scrape(t *testing.T, token, authorization string) (int, string) {
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	router := gin.New()
+	router.GET("/metrics", Handler(token))
+	router.ServeHTTP(rec, req)
+	body, err := io.ReadAll(rec.Body)
+	assert.NoError(t, err)
+	return rec.Code, string(body)
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/products/:productId", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/products/1", "/products/2", "/missing"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	_, body := scrape(t, "", "")
+	assert.Contains(t, body, `ecommerce_http_request_duration_seconds_count{method="GET",route="/products/:productId",status="200"} 2`)
+	assert.Contains(t, body, `ecommerce_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+}
+
+func TestHandlerToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	status, _ := scrape(t, "s3cret", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, body := scrape(t, "s3cret", "Bearer s3cret")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "ecommerce_purchases_total")
+}
+
+func TestGormPlugin(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Use(GormPlugin{}))
+
+	type Product struct {
+		ID    uint
+		Title string
+	}
+	var products []Product
+	db.Find(&products)
+	db.Create(&Product{Title: "Shirt"})
+
+	_, body := scrape(t, "", "")
+	assert.Contains(t, body, `ecommerce_db_query_duration_seconds_count{operation="query",table="products"} 1`)
+	assert.Contains(t, body, `ecommerce_db_query_duration_seconds_count{operation="create",table="products"} 1`)
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"e-commerce/money"
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -11,30 +14,274 @@ type User struct {
 	Password           string               `json:"password"`
 	Role               string               `json:"role"`
 	Balance            int                  `json:"balance"`
+	Addresses          []Address            `gorm:"foreignKey:UserID" json:"addresses,omitempty"`
 	TransactionHistory []TransactionHistory `gorm:"foreignKey:UserID" json:"transaction_history"`
 }
 
+// Address is an entry of a user's address book. Purchases are shipped to
+// the chosen address, or to the default one.
+type Address struct {
+	gorm.Model `swaggerignore:"true"`
+	UserID     uint   `gorm:"index" json:"user_id"`
+	Label      string `json:"label"`
+	ShippingAddress
+	IsDefault bool `json:"is_default"`
+}
+
+// ShippingAddress is where a purchase is delivered. Transactions keep a
+// copy so later address book edits don't change past orders.
+type ShippingAddress struct {
+	RecipientName string `json:"recipient_name"`
+	Phone         string `json:"phone"`
+	Street        string `json:"street"`
+	City          string `json:"city"`
+	Province      string `json:"province"`
+	PostalCode    string `json:"postal_code"`
+}
+
+// Product prices, like every plain int amount in the store, are in whole
+// rupiah. DisplayPrice is Price converted to the currency a client asked for.
+// A SKU is unique among products that aren't deleted; the partial index for
+// it is created by the migration in config.
 type Product struct {
 	gorm.Model         `swaggerignore:"true"`
+	SKU                string               `json:"sku"`
 	Title              string               `json:"title"`
 	Price              int                  `json:"price"`
 	Stock              int                  `json:"stock"`
 	CategoryID         uint                 `json:"category_id"`
+	ReorderThreshold   int                  `json:"reorder_threshold"`
+	Weight             int                  `json:"weight"`
+	Available          int                  `gorm:"-" json:"available"`
+	Variants           []ProductVariant     `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"variants,omitempty"`
+	Images             []ProductImage       `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"images,omitempty"`
+	Prices             []ProductPrice       `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"prices,omitempty"`
+	DisplayPrice       *money.Money         `gorm:"-" json:"display_price,omitempty"`
 	TransactionHistory []TransactionHistory `gorm:"foreignKey:ProductID" json:"transaction_history"`
 }
 
+// ProductPrice is a product's price in a currency other than rupiah, in
+// that currency's minor units. Currencies without one are converted from
+// Price at the current exchange rate.
+type ProductPrice struct {
+	gorm.Model `swaggerignore:"true"`
+	ProductID  uint   `gorm:"uniqueIndex:idx_product_price_currency" json:"product_id"`
+	Currency   string `gorm:"size:3;uniqueIndex:idx_product_price_currency" json:"currency"`
+	Amount     int64  `json:"amount"`
+}
+
+func (pp ProductPrice) Money() money.Money {
+	return money.New(pp.Amount, pp.Currency)
+}
+
+// ProductVariant is a sellable option of a product, e.g. a size and colour.
+// Price overrides the product price when set. Like product SKUs, variant
+// SKUs are only unique among variants that aren't deleted.
+type ProductVariant struct {
+	gorm.Model   `swaggerignore:"true"`
+	ProductID    uint         `gorm:"index" json:"product_id"`
+	SKU          string       `json:"sku"`
+	Size         string       `json:"size"`
+	Color        string       `json:"color"`
+	Price        *int         `json:"price"`
+	Stock        int          `json:"stock"`
+	Available    int          `gorm:"-" json:"available"`
+	DisplayPrice *money.Money `gorm:"-" json:"display_price,omitempty"`
+}
+
+// ProductImage is one entry of a product gallery, ordered by Position.
+// Key and ThumbnailKey point into the configured BlobStore.
+type ProductImage struct {
+	gorm.Model   `swaggerignore:"true"`
+	ProductID    uint   `gorm:"index" json:"product_id"`
+	Key          string `json:"-"`
+	ThumbnailKey string `json:"-"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Position     int    `json:"position"`
+	URL          string `gorm:"-" json:"url"`
+	ThumbnailURL string `gorm:"-" json:"thumbnail_url"`
+}
+
+// InventoryMovement is one entry of the stock journal. Quantity is signed,
+// so the stock of a product (or of a variant when VariantID is set) always
+// equals the sum of its movements.
+type InventoryMovement struct {
+	gorm.Model    `swaggerignore:"true"`
+	ProductID     uint   `gorm:"index" json:"product_id"`
+	VariantID     *uint  `gorm:"index" json:"variant_id"`
+	UserID        uint   `json:"user_id"`
+	TransactionID *uint  `json:"transaction_id"`
+	Type          string `json:"type"`
+	Quantity      int    `json:"quantity"`
+	StockAfter    int    `json:"stock_after"`
+	Note          string `json:"note"`
+}
+
+// StockReservation holds stock for a user's pending checkout until
+// ExpiresAt. Available-to-sell is stock minus active reservations.
+type StockReservation struct {
+	gorm.Model `swaggerignore:"true"`
+	UserID     uint      `gorm:"index" json:"user_id"`
+	ProductID  uint      `gorm:"index" json:"product_id"`
+	VariantID  *uint     `gorm:"index" json:"variant_id"`
+	Quantity   int       `json:"quantity"`
+	Status     string    `gorm:"index" json:"status"`
+	ExpiresAt  time.Time `gorm:"index" json:"expires_at"`
+}
+
+// StockEvent is emitted in the same transaction as the stock change that
+// caused it and delivered later by the stock alert dispatcher. A dispatcher
+// claims an event by setting DispatchingAt before delivering it, so two
+// instances never notify twice. Failed deliveries are counted in Attempts
+// and retried until the limit is reached.
+type StockEvent struct {
+	gorm.Model    `swaggerignore:"true"`
+	ProductID     uint       `gorm:"index" json:"product_id"`
+	VariantID     *uint      `json:"variant_id"`
+	Type          string     `json:"type"`
+	Stock         int        `json:"stock"`
+	Threshold     int        `json:"threshold"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `json:"last_error"`
+	DispatchingAt *time.Time `json:"dispatching_at"`
+	DispatchedAt  *time.Time `gorm:"index" json:"dispatched_at"`
+}
+
+// RestockSubscription asks for a notification when a product, or one of
+// its variants, is back in stock.
+type RestockSubscription struct {
+	gorm.Model `swaggerignore:"true"`
+	UserID     uint       `gorm:"index" json:"user_id"`
+	ProductID  uint       `gorm:"index" json:"product_id"`
+	VariantID  *uint      `json:"variant_id"`
+	NotifiedAt *time.Time `json:"notified_at"`
+	User       User       `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// Coupon discounts a purchase. Value is a percentage, an amount or a number
+// of free units depending on Type. Zero limits mean unlimited, and a nil
+// ProductID or CategoryID means the coupon applies to every product.
+type Coupon struct {
+	gorm.Model     `swaggerignore:"true"`
+	Code           string     `gorm:"uniqueIndex" json:"code"`
+	Type           string     `json:"type"`
+	Value          int        `json:"value"`
+	MinSpend       int        `json:"min_spend"`
+	StartsAt       *time.Time `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+	MaxRedemptions int        `json:"max_redemptions"`
+	MaxPerUser     int        `json:"max_per_user"`
+	ProductID      *uint      `json:"product_id"`
+	CategoryID     *uint      `json:"category_id"`
+	Active         bool       `json:"active"`
+}
+
+type CouponRedemption struct {
+	gorm.Model    `swaggerignore:"true"`
+	CouponID      uint  `gorm:"index" json:"coupon_id"`
+	UserID        uint  `gorm:"index" json:"user_id"`
+	TransactionID *uint `json:"transaction_id"`
+	Discount      int   `json:"discount"`
+}
+
+// Invoice gives a transaction its invoice number. Numbers restart at one
+// every year and have no gaps, see InvoiceSequence.
+type Invoice struct {
+	gorm.Model    `swaggerignore:"true"`
+	TransactionID uint      `gorm:"uniqueIndex" json:"transaction_id"`
+	Number        string    `gorm:"uniqueIndex" json:"number"`
+	Year          int       `json:"year"`
+	Sequence      int       `json:"sequence"`
+	IssuedAt      time.Time `json:"issued_at"`
+}
+
+// InvoiceSequence is the last invoice number used in a year. Its row is
+// locked while a number is taken.
+type InvoiceSequence struct {
+	Year int `gorm:"primaryKey;autoIncrement:false"`
+	Last int
+}
+
+// PriceHistory records every change to a product's price, so the price a
+// past transaction was charged at can be looked up.
+type PriceHistory struct {
+	gorm.Model        `swaggerignore:"true"`
+	ProductID         uint   `gorm:"index" json:"product_id"`
+	UserID            uint   `json:"user_id"`
+	ScheduledChangeID *uint  `json:"scheduled_change_id"`
+	OldPrice          int    `json:"old_price"`
+	NewPrice          int    `json:"new_price"`
+	Reason            string `json:"reason"`
+}
+
+// ScheduledPriceChange sets a product's price at StartsAt. When EndsAt is
+// set the previous price, kept in RevertPrice, is restored at EndsAt.
+type ScheduledPriceChange struct {
+	gorm.Model  `swaggerignore:"true"`
+	ProductID   uint       `gorm:"index" json:"product_id"`
+	UserID      uint       `json:"user_id"`
+	Price       int        `json:"price"`
+	StartsAt    time.Time  `gorm:"index" json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	RevertPrice *int       `json:"revert_price"`
+	Status      string     `gorm:"index" json:"status"`
+}
+
+// Category groups products. TaxRate overrides the default tax rate for its
+// products, in hundredths of a percent (1100 is 11%). The foreign key
+// refuses to remove a category row that products still point to. Since
+// categories and products are soft deleted, the category repository applies
+// the same rule itself: it refuses to delete a category that still has
+// products, or deletes or moves them first.
 type Category struct {
 	gorm.Model        `swaggerignore:"true"`
 	Type              string    `json:"type"`
 	SoldProductAmount int       `json:"sold_product_amount"`
-	Products          []Product `gorm:"foreignKey:CategoryID" json:"products"`
+	TaxRate           *int      `json:"tax_rate"`
+	Products          []Product `gorm:"foreignKey:CategoryID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT" json:"products"`
 }
 
 type TransactionHistory struct {
-	gorm.Model `swaggerignore:"true"`
-	ProductID  uint    `json:"product_id"`
-	UserID     uint    `json:"user_id"`
-	Quantity   int     `json:"quantity"`
-	TotalPrice int     `json:"total_price"`
-	Product    Product `gorm:"foreignKey:ProductID" json:"product"`
+	gorm.Model      `swaggerignore:"true"`
+	ProductID       uint            `json:"product_id"`
+	VariantID       *uint           `json:"variant_id"`
+	UserID          uint            `json:"user_id"`
+	Quantity        int             `json:"quantity"`
+	Subtotal        int             `json:"subtotal"`
+	Discount        int             `json:"discount"`
+	CouponID        *uint           `json:"coupon_id"`
+	TaxName         string          `json:"tax_name"`
+	TaxRate         int             `json:"tax_rate"`
+	TaxBase         int             `json:"tax_base"`
+	Tax             int             `json:"tax"`
+	TaxIncluded     bool            `json:"tax_included"`
+	ShippingCost    int             `json:"shipping_cost"`
+	AddressID       *uint           `json:"address_id"`
+	ShippingAddress ShippingAddress `gorm:"embedded;embeddedPrefix:shipping_" json:"shipping_address"`
+	TotalPrice      int             `json:"total_price"`
+	Product         Product         `gorm:"foreignKey:ProductID" json:"product"`
+	Variant         *ProductVariant `gorm:"foreignKey:VariantID" json:"variant,omitempty"`
+}
+
+// ScheduledJob is a background job run whenever its cron Schedule (in UTC)
+// matches. Only one instance runs a job at a time: the instance that claims
+// it holds it in LockedBy until LockedUntil.
+type ScheduledJob struct {
+	gorm.Model  `swaggerignore:"true"`
+	Name        string     `gorm:"uniqueIndex" json:"name"`
+	Type        string     `json:"type"`
+	Schedule    string     `json:"schedule"`
+	Recipients  string     `json:"recipients"`
+	PeriodDays  int        `json:"period_days"`
+	Enabled     bool       `json:"enabled"`
+	NextRunAt   time.Time  `gorm:"index" json:"next_run_at"`
+	LastRunAt   *time.Time `json:"last_run_at"`
+	LastStatus  string     `json:"last_status"`
+	LastError   string     `json:"last_error"`
+	LastOutput  string     `json:"last_output"`
+	LockedBy    string     `json:"-"`
+	LockedUntil *time.Time `json:"-"`
 }
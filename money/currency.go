@@ -0,0 +1,44 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency. MinorUnits is the number of digits after
+// the decimal point that amounts are stored with.
+type Currency struct {
+	Code       string
+	MinorUnits int
+	Symbol     string
+}
+
+// BaseCurrency is the currency every plain int amount in the store is in.
+const BaseCurrency = "IDR"
+
+// Rupiah is kept in whole rupiah, since sen are no longer in use.
+var currencies = map[string]Currency{
+	"IDR": {Code: "IDR", MinorUnits: 0, Symbol: "Rp"},
+	"USD": {Code: "USD", MinorUnits: 2, Symbol: "$"},
+	"EUR": {Code: "EUR", MinorUnits: 2, Symbol: "€"},
+	"SGD": {Code: "SGD", MinorUnits: 2, Symbol: "S$"},
+	"MYR": {Code: "MYR", MinorUnits: 2, Symbol: "RM"},
+	"JPY": {Code: "JPY", MinorUnits: 0, Symbol: "¥"},
+}
+
+// LookupCurrency returns the currency with the given code, in any case.
+func LookupCurrency(code string) (Currency, error) {
+	currency, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, fmt.Errorf("unsupported currency %q", code)
+	}
+	return currency, nil
+}
+
+func (c Currency) scale() int64 {
+	scale := int64(1)
+	for i := 0; i < c.MinorUnits; i++ {
+		scale *= 10
+	}
+	return scale
+}
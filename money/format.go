@@ -0,0 +1,75 @@
+package money
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Locale describes how amounts are written.
+type Locale struct {
+	Tag       string
+	Thousands string
+	Decimal   string
+	// SymbolSpace puts a space between the currency symbol and the amount.
+	SymbolSpace bool
+}
+
+var (
+	// LocaleID writes Rp1.500.000 and $10,50.
+	LocaleID = Locale{Tag: "id-ID", Thousands: ".", Decimal: ","}
+	// LocaleEN writes Rp1,500,000 and $10.50.
+	LocaleEN = Locale{Tag: "en-US", Thousands: ",", Decimal: "."}
+)
+
+// LookupLocale returns the locale for a language tag such as "id-ID" or
+// "en", falling back to LocaleID.
+func LookupLocale(tag string) Locale {
+	if strings.HasPrefix(strings.ToLower(tag), "en") {
+		return LocaleEN
+	}
+	return LocaleID
+}
+
+// Format writes m with its currency symbol and locale separators. Unknown
+// currencies are written with their code and no minor units.
+func Format(m Money, locale Locale) string {
+	currency, err := LookupCurrency(m.Currency)
+	if err != nil {
+		currency = Currency{Code: m.Currency, Symbol: m.Currency + " "}
+	}
+
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	scale := currency.scale()
+	text := groupThousands(strconv.FormatInt(amount/scale, 10), locale.Thousands)
+	if currency.MinorUnits > 0 {
+		fraction := strconv.FormatInt(amount%scale, 10)
+		text += locale.Decimal + strings.Repeat("0", currency.MinorUnits-len(fraction)) + fraction
+	}
+
+	symbol := currency.Symbol
+	if locale.SymbolSpace && !strings.HasSuffix(symbol, " ") {
+		symbol += " "
+	}
+	return sign + symbol + text
+}
+
+// FormatNumber writes n with the thousands separator of locale.
+func FormatNumber(n int64, locale Locale) string {
+	if n < 0 {
+		return "-" + groupThousands(strconv.FormatInt(-n, 10), locale.Thousands)
+	}
+	return groupThousands(strconv.FormatInt(n, 10), locale.Thousands)
+}
+
+func groupThousands(digits, separator string) string {
+	n := len(digits)
+	if n <= 3 {
+		return digits
+	}
+	return groupThousands(digits[:n-3], separator) + separator + digits[n-3:]
+}
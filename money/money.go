@@ -0,0 +1,57 @@
+// Package money formats and converts amounts in different currencies.
+//
+// Stored amounts, such as product prices, balances and transaction totals,
+// stay plain ints in whole rupiah (BaseCurrency). Money is used where the
+// currency matters: prices kept in other currencies, prices converted for
+// display and locale-aware formatting.
+package money
+
+import (
+	"errors"
+	"strings"
+)
+
+// Money is an amount in the minor units of a currency, so 1050 USD is
+// $10.50 and 15000 IDR is Rp15.000.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `gorm:"size:3" json:"currency"`
+}
+
+var ErrCurrencyMismatch = errors.New("money: currencies do not match")
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// IDR is an amount of whole rupiah.
+func IDR(amount int) Money {
+	return New(int64(amount), "IDR")
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Mul(quantity int) Money {
+	return Money{Amount: m.Amount * int64(quantity), Currency: m.Currency}
+}
+
+// String formats m for Indonesian readers.
+func (m Money) String() string {
+	return Format(m, LocaleID)
+}
@@ -0,0 +1,61 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "Rp1.500.000", Format(IDR(1500000), LocaleID))
+	assert.Equal(t, "Rp1,500,000", Format(IDR(1500000), LocaleEN))
+	assert.Equal(t, "Rp999", Format(IDR(999), LocaleID))
+	assert.Equal(t, "-Rp15.000", Format(IDR(-15000), LocaleID))
+	assert.Equal(t, "$1,234.05", Format(New(123405, "usd"), LocaleEN))
+	assert.Equal(t, "$1.234,05", Format(New(123405, "USD"), LocaleID))
+	assert.Equal(t, "Rp 15.000", Format(IDR(15000), Locale{Thousands: ".", Decimal: ",", SymbolSpace: true}))
+	assert.Equal(t, "Rp15.000", IDR(15000).String())
+}
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "50.000.000", FormatNumber(50000000, LocaleID))
+	assert.Equal(t, "50,000,000", FormatNumber(50000000, LocaleEN))
+	assert.Equal(t, "-1,500", FormatNumber(-1500, LocaleEN))
+	assert.Equal(t, "12", FormatNumber(12, LocaleID))
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	total, err := IDR(15000).Mul(3).Add(IDR(500))
+	assert.NoError(t, err)
+	assert.Equal(t, IDR(45500), total)
+
+	_, err = IDR(15000).Sub(New(100, "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestConvert(t *testing.T) {
+	rates, err := ParseStaticRates("IDR", "USD=0.0000625, JPY=0.0095")
+	assert.NoError(t, err)
+
+	usd, err := Convert(IDR(160000), "USD", rates)
+	assert.NoError(t, err)
+	assert.Equal(t, New(1000, "USD"), usd)
+
+	idr, err := Convert(New(1000, "USD"), "IDR", rates)
+	assert.NoError(t, err)
+	assert.Equal(t, IDR(160000), idr)
+
+	jpy, err := Convert(New(1000, "USD"), "JPY", rates)
+	assert.NoError(t, err)
+	assert.Equal(t, New(1520, "JPY"), jpy)
+
+	_, err = Convert(IDR(1000), "EUR", rates)
+	assert.EqualError(t, err, "no exchange rate for EUR")
+}
+
+func TestParseStaticRatesInvalid(t *testing.T) {
+	_, err := ParseStaticRates("IDR", "USD=abc")
+	assert.Error(t, err)
+	_, err = ParseStaticRates("IDR", "XYZ=1")
+	assert.Error(t, err)
+}
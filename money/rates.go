@@ -0,0 +1,87 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RateProvider returns how many units of to one unit of from is worth.
+type RateProvider interface {
+	Rate(from, to string) (float64, error)
+}
+
+// StaticRates are fixed rates against Base, e.g. Base "IDR" with USD at
+// 0.000063 means Rp1 is worth $0.000063.
+type StaticRates struct {
+	Base  string
+	Rates map[string]float64
+}
+
+// ParseStaticRates reads rates written as "USD=0.000063,SGD=0.000085".
+func ParseStaticRates(base, spec string) (StaticRates, error) {
+	rates := StaticRates{Base: strings.ToUpper(base), Rates: map[string]float64{}}
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		code, value, ok := strings.Cut(pair, "=")
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil || rate <= 0 {
+			return StaticRates{}, fmt.Errorf("invalid exchange rate %q", pair)
+		}
+		if _, err := LookupCurrency(strings.TrimSpace(code)); err != nil {
+			return StaticRates{}, err
+		}
+		rates.Rates[strings.ToUpper(strings.TrimSpace(code))] = rate
+	}
+	return rates, nil
+}
+
+func (sr StaticRates) Rate(from, to string) (float64, error) {
+	fromRate, err := sr.rate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := sr.rate(to)
+	if err != nil {
+		return 0, err
+	}
+	return toRate / fromRate, nil
+}
+
+func (sr StaticRates) rate(code string) (float64, error) {
+	code = strings.ToUpper(code)
+	if code == sr.Base {
+		return 1, nil
+	}
+	rate, ok := sr.Rates[code]
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate for %s", code)
+	}
+	return rate, nil
+}
+
+// Convert changes m into currency to at the provider's rate, rounding to
+// the nearest minor unit.
+func Convert(m Money, to string, provider RateProvider) (Money, error) {
+	to = strings.ToUpper(to)
+	if m.Currency == to {
+		return m, nil
+	}
+	from, err := LookupCurrency(m.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	target, err := LookupCurrency(to)
+	if err != nil {
+		return Money{}, err
+	}
+	rate, err := provider.Rate(from.Code, target.Code)
+	if err != nil {
+		return Money{}, err
+	}
+	major := float64(m.Amount) / float64(from.scale())
+	return New(int64(math.Round(major*rate*float64(target.scale()))), target.Code), nil
+}
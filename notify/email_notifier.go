@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"fmt"
+)
+
+// EmailNotifier sends notifications as plain text email through a Mailer.
+type EmailNotifier struct {
+	Mailer Mailer
+}
+
+func NewEmailNotifier(mailer Mailer) *EmailNotifier {
+	return &EmailNotifier{Mailer: mailer}
+}
+
+func (en *EmailNotifier) Notify(notification Notification) error {
+	if notification.Recipient == "" {
+		return fmt.Errorf("notification %q has no recipient", notification.Event)
+	}
+	return en.Mailer.Send(Mail{
+		To:      []string{notification.Recipient},
+		Subject: notification.Subject,
+		Body:    notification.Message,
+	})
+}
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailNotifier(t *testing.T) {
+	var sentTo []string
+	var sentMsg string
+	mailer := NewSMTPMailer("localhost", "25", "", "", "shop@example.com")
+	mailer.send = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		sentTo, sentMsg = to, string(msg)
+		return nil
+	}
+	notifier := NewEmailNotifier(mailer)
+
+	err := notifier.Notify(Notification{Event: "low_stock", Recipient: "admin@example.com", Subject: "Stok menipis: Kursi Ergonomis — 2 tersisa", Message: "AC has 2 left"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin@example.com"}, sentTo)
+	msg, err := mail.ReadMessage(strings.NewReader(sentMsg))
+	assert.NoError(t, err)
+	assert.NotContains(t, msg.Header["Subject"][0], "—")
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Stok menipis: Kursi Ergonomis — 2 tersisa", subject)
+	assert.Contains(t, sentMsg, "AC has 2 left")
+}
+
+func TestEmailNotifierRequiresRecipient(t *testing.T) {
+	notifier := NewEmailNotifier(LogMailer{})
+
+	assert.Error(t, notifier.Notify(Notification{Event: "low_stock", Subject: "Low stock"}))
+}
@@ -0,0 +1,11 @@
+package notify
+
+import "log/slog"
+
+// LogNotifier writes notifications to the default structured logger.
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(notification Notification) error {
+	slog.Info("notification", "event", notification.Event, "recipient", notification.Recipient, "subject", notification.Subject, "message", notification.Message)
+	return nil
+}
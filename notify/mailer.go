@@ -0,0 +1,21 @@
+package notify
+
+// Attachment is a file attached to a mail.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Mail is an email with optional attachments.
+type Mail struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Mailer sends email, such as scheduled report digests.
+type Mailer interface {
+	Send(mail Mail) error
+}
@@ -0,0 +1,17 @@
+package notify
+
+// Notification is a message for a single recipient. Recipient is an email
+// address for email delivery and is passed through as-is to webhooks.
+type Notification struct {
+	Event     string                 `json:"event"`
+	Recipient string                 `json:"recipient"`
+	Subject   string                 `json:"subject"`
+	Message   string                 `json:"message"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// Notifier delivers notifications through a channel such as the log, email
+// or a webhook.
+type Notifier interface {
+	Notify(notification Notification) error
+}
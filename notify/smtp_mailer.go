@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+)
+
+// SMTPMailer sends mail over SMTP as multipart MIME messages.
+type SMTPMailer struct {
+	Addr string
+	From string
+	Auth smtp.Auth
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{Addr: host + ":" + port, From: from, Auth: auth, send: smtp.SendMail}
+}
+
+func (sm *SMTPMailer) Send(mail Mail) error {
+	if len(mail.To) == 0 {
+		return errors.New("mail has no recipients")
+	}
+	var msg strings.Builder
+	writer := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "From: %s\r\n", sm.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(mail.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mail.Subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", writer.Boundary())
+
+	body, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=UTF-8"}})
+	if err != nil {
+		return err
+	}
+	body.Write([]byte(mail.Body))
+
+	for _, attachment := range mail.Attachments {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		})
+		if err != nil {
+			return err
+		}
+		encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+		for len(encoded) > 76 {
+			part.Write([]byte(encoded[:76] + "\r\n"))
+			encoded = encoded[76:]
+		}
+		part.Write([]byte(encoded + "\r\n"))
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return sm.send(sm.Addr, sm.Auth, sm.From, mail.To, []byte(msg.String()))
+}
+
+// LogMailer writes mail to the default structured logger instead of sending it.
+type LogMailer struct{}
+
+func (LogMailer) Send(mail Mail) error {
+	filenames := make([]string, 0, len(mail.Attachments))
+	for _, attachment := range mail.Attachments {
+		filenames = append(filenames, attachment.Filename)
+	}
+	LogNotifier{}.Notify(Notification{
+		Event:     "mail",
+		Recipient: strings.Join(mail.To, ", "),
+		Subject:   mail.Subject,
+		Message:   fmt.Sprintf("%s [attachments: %s]", mail.Body, strings.Join(filenames, ", ")),
+	})
+	return nil
+}
@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTPMailer(t *testing.T) {
+	var sentTo []string
+	var sentMsg string
+	mailer := NewSMTPMailer("localhost", "25", "", "", "shop@example.com")
+	mailer.send = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		sentTo, sentMsg = to, string(msg)
+		return nil
+	}
+
+	err := mailer.Send(Mail{
+		To:          []string{"finance@example.com", "owner@example.com"},
+		Subject:     "Daily sales",
+		Body:        "12 orders",
+		Attachments: []Attachment{{Filename: "summary.csv", ContentType: "text/csv", Content: []byte("orders\n12\n")}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"finance@example.com", "owner@example.com"}, sentTo)
+
+	msg, err := mail.ReadMessage(strings.NewReader(sentMsg))
+	assert.NoError(t, err)
+	assert.Equal(t, "Daily sales", msg.Header.Get("Subject"))
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	body, err := reader.NextPart()
+	assert.NoError(t, err)
+	text, _ := io.ReadAll(body)
+	assert.Equal(t, "12 orders", string(text))
+
+	attachment, err := reader.NextPart()
+	assert.NoError(t, err)
+	assert.Equal(t, "summary.csv", attachment.FileName())
+	content, _ := io.ReadAll(attachment)
+	assert.Equal(t, "b3JkZXJzCjEyCg==\r\n", string(content))
+}
+
+func TestSMTPMailerWithoutRecipients(t *testing.T) {
+	assert.EqualError(t, NewSMTPMailer("localhost", "25", "", "", "shop@example.com").Send(Mail{Subject: "Daily sales"}), "mail has no recipients")
+}
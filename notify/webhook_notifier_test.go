@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	var received Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	err := notifier.Notify(Notification{Event: "back_in_stock", Recipient: "buyer@example.com", Subject: "AC is back"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "back_in_stock", received.Event)
+	assert.Equal(t, "buyer@example.com", received.Recipient)
+}
+
+func TestWebhookNotifierError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL).Notify(Notification{Event: "low_stock"})
+
+	assert.EqualError(t, err, "webhook responded with 502 Bad Gateway")
+}
@@ -1,30 +1,71 @@
 package repository
 
-import "e-commerce/entity"
+import (
+	"context"
+	"e-commerce/entity"
+	"time"
+)
 
 type ProductRepo interface {
-	FindAllProduct() []entity.Product
-	CreateProduct(product entity.Product) error
-	FindProductByID(productID string) (*entity.Product, error)
-	UpdateProduct(product *entity.Product) error
-	DeleteProduct(product *entity.Product) error
+	FindAllProduct(ctx context.Context) []entity.Product
+	CreateProduct(ctx context.Context, product entity.Product) error
+	FindProductByID(ctx context.Context, productID string) (*entity.Product, error)
+	UpdateProduct(ctx context.Context, product *entity.Product) error
+	DeleteProduct(ctx context.Context, product *entity.Product) error
 }
 type CategoryRepo interface {
-	FindAll() []entity.Category
-	FindByID(id uint) (*entity.Category, error)
-	Create(category entity.Category) error
-	FindByType(categoryType string) (*entity.Category, error)
-	Update(category *entity.Category) error
-	Delete(category *entity.Category) error
+	FindAll(ctx context.Context) []entity.Category
+	FindByID(ctx context.Context, id uint) (*entity.Category, error)
+	Create(ctx context.Context, category entity.Category) error
+	FindByType(ctx context.Context, categoryType string) (*entity.Category, error)
+	Update(ctx context.Context, category *entity.Category) error
+	Delete(ctx context.Context, category *entity.Category, deletion entity.CategoryDeletion) (int64, error)
 }
 type UserRepo interface {
-	Create(user *entity.User) error
-	FindByEmail(email string) (*entity.User, error)
-	UpdateBalance(user *entity.User) error
+	Create(ctx context.Context, user *entity.User) error
+	FindByEmail(ctx context.Context, email string) (*entity.User, error)
+	UpdateBalance(ctx context.Context, user *entity.User) error
 }
 
 type TransactionRepo interface {
-	CreateTransactionHistory(transaction entity.TransactionHistory) error
-	GetTransactionHistoryByUserID(userID uint) ([]entity.TransactionHistory, error)
-	GetAllTransactionHistory() ([]entity.TransactionHistory, error)
+	CreateTransactionHistory(ctx context.Context, transaction entity.TransactionHistory) error
+	GetTransactionHistoryByUserID(ctx context.Context, userID uint) ([]entity.TransactionHistory, error)
+	GetAllTransactionHistory(ctx context.Context) ([]entity.TransactionHistory, error)
+	FindTransactions(ctx context.Context, filter entity.TransactionFilter) ([]entity.TransactionHistory, int64, error)
+	FindTransactionByID(ctx context.Context, id uint) (*entity.TransactionHistory, error)
+}
+
+type ReservationRepo interface {
+	ExpireBefore(ctx context.Context, now time.Time) (int64, error)
+}
+
+type StockAlertRepo interface {
+	FindPendingEvents(ctx context.Context, limit int) ([]entity.StockEvent, error)
+	ClaimEvent(ctx context.Context, eventID uint, at, staleBefore time.Time) (bool, error)
+	MarkEventDispatched(ctx context.Context, eventID uint, at time.Time) error
+	MarkEventFailed(ctx context.Context, eventID uint, reason string) error
+	FindWaitingSubscriptions(ctx context.Context, productID uint, variantID *uint) ([]entity.RestockSubscription, error)
+	MarkSubscriptionNotified(ctx context.Context, subscriptionID uint, at time.Time) error
+	FindProductTitle(ctx context.Context, productID uint) (string, error)
+}
+
+type PriceScheduleRepo interface {
+	FindDue(ctx context.Context, now time.Time) ([]entity.ScheduledPriceChange, error)
+	FindProductPrice(ctx context.Context, productID uint) (int, error)
+	ApplyScheduledChange(ctx context.Context, change *entity.ScheduledPriceChange, price int, reason string, at time.Time) (bool, error)
+	UpdateStatus(ctx context.Context, change *entity.ScheduledPriceChange) error
+}
+
+type ReportRepo interface {
+	RevenueByPeriod(ctx context.Context, r entity.ReportRange, granularity string) ([]entity.RevenuePoint, error)
+	TopProducts(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error)
+	TopCategories(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error)
+	TopCustomers(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error)
+	Summary(ctx context.Context, r entity.ReportRange) (*entity.SalesSummary, error)
+}
+
+type JobRepo interface {
+	FindDue(ctx context.Context, now time.Time) ([]entity.ScheduledJob, error)
+	Claim(ctx context.Context, jobID uint, owner string, now, until time.Time) (bool, error)
+	Finish(ctx context.Context, job *entity.ScheduledJob) error
 }
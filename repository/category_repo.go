@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"e-commerce/entity"
+	"e-commerce/models"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type categoryRepo struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepo(db *gorm.DB) CategoryRepo {
+	return categoryRepo{db: db}
+}
+
+func (cr categoryRepo) FindAll(ctx context.Context) []entity.Category {
+	var records []models.Category
+	if err := cr.db.WithContext(ctx).Order("id").Find(&records).Error; err != nil {
+		return nil
+	}
+	categories := make([]entity.Category, 0, len(records))
+	for _, record := range records {
+		categories = append(categories, categoryEntity(record))
+	}
+	return categories
+}
+
+func (cr categoryRepo) FindByID(ctx context.Context, id uint) (*entity.Category, error) {
+	return cr.findBy(ctx, "id = ?", id)
+}
+
+func (cr categoryRepo) FindByType(ctx context.Context, categoryType string) (*entity.Category, error) {
+	return cr.findBy(ctx, "type = ?", categoryType)
+}
+
+func (cr categoryRepo) findBy(ctx context.Context, query string, value interface{}) (*entity.Category, error) {
+	var record models.Category
+	err := cr.db.WithContext(ctx).Where(query, value).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	category := categoryEntity(record)
+	return &category, nil
+}
+
+func (cr categoryRepo) Create(ctx context.Context, category entity.Category) error {
+	return cr.db.WithContext(ctx).Create(&models.Category{Type: category.Type, TaxRate: category.TaxRate}).Error
+}
+
+func (cr categoryRepo) Update(ctx context.Context, category *entity.Category) error {
+	return cr.db.WithContext(ctx).Model(&models.Category{}).Where("id = ?", category.ID).
+		Updates(map[string]interface{}{"type": category.Type, "tax_rate": category.TaxRate}).Error
+}
+
+// Delete deletes a category and settles its products in one transaction.
+// Products move to deletion.ReassignTo when it is set and are deleted with
+// deletion.Cascade. With neither, the category is only deleted when it has
+// no products, and otherwise the number it still has is returned.
+func (cr categoryRepo) Delete(ctx context.Context, category *entity.Category, deletion entity.CategoryDeletion) (int64, error) {
+	var remaining int64
+	err := cr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Category{}, category.ID).Error; err != nil {
+			return err
+		}
+		products := tx.Model(&models.Product{}).Where("category_id = ?", category.ID)
+		switch {
+		case deletion.ReassignTo != 0:
+			if err := products.Update("category_id", deletion.ReassignTo).Error; err != nil {
+				return err
+			}
+		case deletion.Cascade:
+			// Schedules of deleted products can never be applied
+			if err := tx.Model(&models.ScheduledPriceChange{}).
+				Where("status IN ? AND product_id IN (?)", []string{entity.PriceChangePending, entity.PriceChangeActive},
+					tx.Model(&models.Product{}).Select("id").Where("category_id = ?", category.ID)).
+				Update("status", entity.PriceChangeCancelled).Error; err != nil {
+				return err
+			}
+			if err := products.Delete(&models.Product{}).Error; err != nil {
+				return err
+			}
+		default:
+			if err := products.Count(&remaining).Error; err != nil || remaining > 0 {
+				return err
+			}
+		}
+		return tx.Delete(&models.Category{}, category.ID).Error
+	})
+	return remaining, err
+}
+
+func categoryEntity(record models.Category) entity.Category {
+	return entity.Category{
+		ID:                record.ID,
+		Type:              record.Type,
+		SoldProductAmount: record.SoldProductAmount,
+		TaxRate:           record.TaxRate,
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"e-commerce/entity"
 
 	"github.com/stretchr/testify/mock"
@@ -10,8 +11,8 @@ type CategoryRepoMock struct {
 	mock.Mock
 }
 
-func (crm *CategoryRepoMock) FindByID(id uint) (*entity.Category, error) {
-	arguments := crm.Mock.Called(id)
+func (crm *CategoryRepoMock) FindByID(ctx context.Context, id uint) (*entity.Category, error) {
+	arguments := crm.Mock.Called(ctx, id)
 	if arguments.Get(0) == nil {
 		return nil, arguments.Error(1)
 	}
@@ -19,32 +20,32 @@ func (crm *CategoryRepoMock) FindByID(id uint) (*entity.Category, error) {
 	return category, arguments.Error(1)
 }
 
-func (crm *CategoryRepoMock) FindByType(categoryType string) (*entity.Category, error) {
-	arguments := crm.Mock.Called(categoryType)
+func (crm *CategoryRepoMock) FindByType(ctx context.Context, categoryType string) (*entity.Category, error) {
+	arguments := crm.Mock.Called(ctx, categoryType)
 	if arguments.Get(0) == nil {
 		return nil, arguments.Error(1)
 	}
 	category := arguments.Get(0).(*entity.Category)
 	return category, arguments.Error(1)
 }
-func (crm *CategoryRepoMock) FindAll() []entity.Category {
-	arguments := crm.Called()
+func (crm *CategoryRepoMock) FindAll(ctx context.Context) []entity.Category {
+	arguments := crm.Called(ctx)
 	if arguments.Get(0) == nil {
 		return nil
 	}
 	categories := arguments.Get(0).([]entity.Category)
 	return categories
 }
-func (crm *CategoryRepoMock) Create(category entity.Category) error {
-	arguments := crm.Called(category)
+func (crm *CategoryRepoMock) Create(ctx context.Context, category entity.Category) error {
+	arguments := crm.Called(ctx, category)
 	return arguments.Error(0)
 }
 
-func (crm *CategoryRepoMock) Update(category *entity.Category) error {
-	arguments := crm.Mock.Called(category)
+func (crm *CategoryRepoMock) Update(ctx context.Context, category *entity.Category) error {
+	arguments := crm.Mock.Called(ctx, category)
 	return arguments.Error(0)
 }
-func (crm *CategoryRepoMock) Delete(category *entity.Category) error {
-	arguments := crm.Mock.Called(category)
-	return arguments.Error(0)
+func (crm *CategoryRepoMock) Delete(ctx context.Context, category *entity.Category, deletion entity.CategoryDeletion) (int64, error) {
+	arguments := crm.Mock.Called(ctx, category, deletion)
+	return arguments.Get(0).(int64), arguments.Error(1)
 }
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"e-commerce/entity"
+	"e-commerce/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type jobRepo struct {
+	db *gorm.DB
+}
+
+func NewJobRepo(db *gorm.DB) JobRepo {
+	return jobRepo{db: db}
+}
+
+func (jr jobRepo) FindDue(ctx context.Context, now time.Time) ([]entity.ScheduledJob, error) {
+	var records []models.ScheduledJob
+	if err := jr.db.WithContext(ctx).
+		Where("enabled AND next_run_at <= ?", now).
+		Where("locked_until IS NULL OR locked_until < ?", now).
+		Order("next_run_at").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	jobs := make([]entity.ScheduledJob, 0, len(records))
+	for _, record := range records {
+		jobs = append(jobs, jobEntity(record))
+	}
+	return jobs, nil
+}
+
+// Claim locks a due job for owner until the given time. It reports false
+// when the job is no longer due or another instance holds it.
+func (jr jobRepo) Claim(ctx context.Context, jobID uint, owner string, now, until time.Time) (bool, error) {
+	result := jr.db.WithContext(ctx).Model(&models.ScheduledJob{}).
+		Where("id = ? AND enabled AND next_run_at <= ?", jobID, now).
+		Where("locked_until IS NULL OR locked_until < ?", now).
+		Updates(map[string]interface{}{"locked_by": owner, "locked_until": until})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Finish saves the outcome of a run and the next run time, and releases the
+// lock.
+func (jr jobRepo) Finish(ctx context.Context, job *entity.ScheduledJob) error {
+	return jr.db.WithContext(ctx).Model(&models.ScheduledJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"next_run_at":  job.NextRunAt,
+		"last_run_at":  job.LastRunAt,
+		"last_status":  job.LastStatus,
+		"last_error":   job.LastError,
+		"last_output":  job.LastOutput,
+		"locked_by":    "",
+		"locked_until": nil,
+	}).Error
+}
+
+func jobEntity(record models.ScheduledJob) entity.ScheduledJob {
+	var recipients []string
+	for _, recipient := range strings.Split(record.Recipients, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return entity.ScheduledJob{
+		ID:         record.ID,
+		Name:       record.Name,
+		Type:       record.Type,
+		Schedule:   record.Schedule,
+		Recipients: recipients,
+		PeriodDays: record.PeriodDays,
+		Enabled:    record.Enabled,
+		NextRunAt:  record.NextRunAt,
+		LastRunAt:  record.LastRunAt,
+		LastStatus: record.LastStatus,
+		LastError:  record.LastError,
+		LastOutput: record.LastOutput,
+	}
+}
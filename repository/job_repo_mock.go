@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"e-commerce/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type JobRepoMock struct {
+	mock.Mock
+}
+
+func (jrm *JobRepoMock) FindDue(ctx context.Context, now time.Time) ([]entity.ScheduledJob, error) {
+	arguments := jrm.Called(ctx, now)
+	if arguments.Get(0) == nil {
+		return nil, arguments.Error(1)
+	}
+	return arguments.Get(0).([]entity.ScheduledJob), arguments.Error(1)
+}
+
+func (jrm *JobRepoMock) Claim(ctx context.Context, jobID uint, owner string, now, until time.Time) (bool, error) {
+	arguments := jrm.Called(ctx, jobID, owner, now, until)
+	return arguments.Bool(0), arguments.Error(1)
+}
+
+func (jrm *JobRepoMock) Finish(ctx context.Context, job *entity.ScheduledJob) error {
+	arguments := jrm.Called(ctx, job)
+	return arguments.Error(0)
+}
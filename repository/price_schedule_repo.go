@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"e-commerce/entity"
+	"e-commerce/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type priceScheduleRepo struct {
+	db *gorm.DB
+}
+
+func NewPriceScheduleRepo(db *gorm.DB) PriceScheduleRepo {
+	return priceScheduleRepo{db: db}
+}
+
+func (psr priceScheduleRepo) FindDue(ctx context.Context, now time.Time) ([]entity.ScheduledPriceChange, error) {
+	var records []models.ScheduledPriceChange
+	if err := psr.db.WithContext(ctx).
+		Where("status = ? AND starts_at <= ?", entity.PriceChangePending, now).
+		Or("status = ? AND ends_at <= ?", entity.PriceChangeActive, now).
+		Order("starts_at").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	changes := make([]entity.ScheduledPriceChange, 0, len(records))
+	for _, record := range records {
+		changes = append(changes, entity.ScheduledPriceChange{
+			ID:          record.ID,
+			ProductID:   record.ProductID,
+			UserID:      record.UserID,
+			Price:       record.Price,
+			StartsAt:    record.StartsAt,
+			EndsAt:      record.EndsAt,
+			RevertPrice: record.RevertPrice,
+			Status:      record.Status,
+		})
+	}
+	return changes, nil
+}
+
+func (psr priceScheduleRepo) FindProductPrice(ctx context.Context, productID uint) (int, error) {
+	var product models.Product
+	if err := psr.db.WithContext(ctx).Select("price").First(&product, productID).Error; err != nil {
+		return 0, err
+	}
+	return product.Price, nil
+}
+
+// ApplyScheduledChange sets the product price, records it in the price
+// history and saves the change's status in one transaction. When a pending
+// change starts, the price it replaces is kept as its RevertPrice. The change
+// is claimed by moving it out of the status it was found in, so it reports
+// false without touching the price when another run got there first.
+func (psr priceScheduleRepo) ApplyScheduledChange(ctx context.Context, change *entity.ScheduledPriceChange, price int, reason string, at time.Time) (bool, error) {
+	from := entity.PriceChangeActive
+	if reason == entity.PriceReasonScheduleStart {
+		from = entity.PriceChangePending
+	}
+	applied := false
+	err := psr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		claimed := tx.Model(&models.ScheduledPriceChange{}).Where("id = ? AND status = ?", change.ID, from).Update("status", change.Status)
+		if claimed.Error != nil || claimed.RowsAffected == 0 {
+			return claimed.Error
+		}
+		var product models.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, change.ProductID).Error; err != nil {
+			return err
+		}
+		oldPrice := product.Price
+		if err := tx.Model(&product).Update("price", price).Error; err != nil {
+			return err
+		}
+		changeID := change.ID
+		history := models.PriceHistory{
+			ProductID:         change.ProductID,
+			UserID:            change.UserID,
+			ScheduledChangeID: &changeID,
+			OldPrice:          oldPrice,
+			NewPrice:          price,
+			Reason:            reason,
+		}
+		history.CreatedAt = at
+		if err := tx.Create(&history).Error; err != nil {
+			return err
+		}
+		if reason == entity.PriceReasonScheduleStart {
+			if err := tx.Model(&models.ScheduledPriceChange{}).Where("id = ?", change.ID).Update("revert_price", oldPrice).Error; err != nil {
+				return err
+			}
+			change.RevertPrice = &oldPrice
+		}
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+func (psr priceScheduleRepo) UpdateStatus(ctx context.Context, change *entity.ScheduledPriceChange) error {
+	return psr.db.WithContext(ctx).Model(&models.ScheduledPriceChange{}).Where("id = ?", change.ID).Update("status", change.Status).Error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"e-commerce/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PriceScheduleRepoMock struct {
+	mock.Mock
+}
+
+func (psrm *PriceScheduleRepoMock) FindDue(ctx context.Context, now time.Time) ([]entity.ScheduledPriceChange, error) {
+	arguments := psrm.Called(ctx, now)
+	if arguments.Get(0) == nil {
+		return nil, arguments.Error(1)
+	}
+	changes := arguments.Get(0).([]entity.ScheduledPriceChange)
+	return changes, arguments.Error(1)
+}
+
+func (psrm *PriceScheduleRepoMock) FindProductPrice(ctx context.Context, productID uint) (int, error) {
+	arguments := psrm.Called(ctx, productID)
+	return arguments.Int(0), arguments.Error(1)
+}
+
+func (psrm *PriceScheduleRepoMock) ApplyScheduledChange(ctx context.Context, change *entity.ScheduledPriceChange, price int, reason string, at time.Time) (bool, error) {
+	arguments := psrm.Called(ctx, change, price, reason, at)
+	return arguments.Bool(0), arguments.Error(1)
+}
+
+func (psrm *PriceScheduleRepoMock) UpdateStatus(ctx context.Context, change *entity.ScheduledPriceChange) error {
+	arguments := psrm.Called(ctx, change)
+	return arguments.Error(0)
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"e-commerce/entity"
 
 	"github.com/stretchr/testify/mock"
@@ -10,8 +11,8 @@ type ProductRepoMock struct {
 	mock.Mock
 }
 
-func (prm *ProductRepoMock) FindAllProduct() []entity.Product {
-	arguments := prm.Called()
+func (prm *ProductRepoMock) FindAllProduct(ctx context.Context) []entity.Product {
+	arguments := prm.Called(ctx)
 	if arguments.Get(0) == nil {
 		return nil
 	}
@@ -19,13 +20,13 @@ func (prm *ProductRepoMock) FindAllProduct() []entity.Product {
 	return products
 }
 
-func (prm *ProductRepoMock) CreateProduct(product entity.Product) error {
-	arguments := prm.Called(product)
+func (prm *ProductRepoMock) CreateProduct(ctx context.Context, product entity.Product) error {
+	arguments := prm.Called(ctx, product)
 	return arguments.Error(0)
 }
 
-func (prm *ProductRepoMock) FindProductByID(productID string) (*entity.Product, error) {
-	arguments := prm.Called(productID)
+func (prm *ProductRepoMock) FindProductByID(ctx context.Context, productID string) (*entity.Product, error) {
+	arguments := prm.Called(ctx, productID)
 	if arguments.Get(0) == nil {
 		return nil, arguments.Error(1)
 	}
@@ -33,11 +34,11 @@ func (prm *ProductRepoMock) FindProductByID(productID string) (*entity.Product,
 	return product, arguments.Error(1)
 }
 
-func (prm *ProductRepoMock) UpdateProduct(product *entity.Product) error {
-	arguments := prm.Called(product)
+func (prm *ProductRepoMock) UpdateProduct(ctx context.Context, product *entity.Product) error {
+	arguments := prm.Called(ctx, product)
 	return arguments.Error(0)
 }
-func (prm *ProductRepoMock) DeleteProduct(product *entity.Product) error {
-	arguments := prm.Called(product)
+func (prm *ProductRepoMock) DeleteProduct(ctx context.Context, product *entity.Product) error {
+	arguments := prm.Called(ctx, product)
 	return arguments.Error(0)
 }
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"e-commerce/entity"
+	"e-commerce/models"
+
+	"gorm.io/gorm"
+)
+
+type reportRepo struct {
+	db *gorm.DB
+}
+
+func NewReportRepo(db *gorm.DB) ReportRepo {
+	return reportRepo{db: db}
+}
+
+const salesTotals = "COUNT(*) AS orders, COALESCE(SUM(transaction_histories.quantity), 0) AS units, COALESCE(SUM(transaction_histories.total_price), 0) AS revenue"
+
+// sales selects the transactions in r.
+func (rr reportRepo) sales(ctx context.Context, r entity.ReportRange) *gorm.DB {
+	return rr.db.WithContext(ctx).Model(&models.TransactionHistory{}).
+		Where("transaction_histories.created_at >= ? AND transaction_histories.created_at < ?", r.From, r.To)
+}
+
+// RevenueByPeriod groups sales by UTC day, week (starting Monday) or month.
+// Periods without sales are left out.
+func (rr reportRepo) RevenueByPeriod(ctx context.Context, r entity.ReportRange, granularity string) ([]entity.RevenuePoint, error) {
+	var points []entity.RevenuePoint
+	err := rr.sales(ctx, r).
+		Select("date_trunc(?, transaction_histories.created_at AT TIME ZONE 'UTC') AS period, "+salesTotals, granularity).
+		Group("period").Order("period").
+		Scan(&points).Error
+	return points, err
+}
+
+func (rr reportRepo) TopProducts(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error) {
+	var items []entity.RankedItem
+	err := rr.sales(ctx, r).
+		Select("products.id AS id, products.title AS name, " + salesTotals).
+		Joins("JOIN products ON products.id = transaction_histories.product_id").
+		Group("products.id, products.title").
+		Order(by + " DESC").Order("products.id").Limit(limit).
+		Scan(&items).Error
+	return items, err
+}
+
+func (rr reportRepo) TopCategories(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error) {
+	var items []entity.RankedItem
+	err := rr.sales(ctx, r).
+		Select("categories.id AS id, categories.type AS name, " + salesTotals).
+		Joins("JOIN products ON products.id = transaction_histories.product_id").
+		Joins("JOIN categories ON categories.id = products.category_id").
+		Group("categories.id, categories.type").
+		Order(by + " DESC").Order("categories.id").Limit(limit).
+		Scan(&items).Error
+	return items, err
+}
+
+func (rr reportRepo) TopCustomers(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error) {
+	var items []entity.RankedItem
+	err := rr.sales(ctx, r).
+		Select("users.id AS id, users.full_name AS name, " + salesTotals).
+		Joins("JOIN users ON users.id = transaction_histories.user_id").
+		Group("users.id, users.full_name").
+		Order(by + " DESC").Order("users.id").Limit(limit).
+		Scan(&items).Error
+	return items, err
+}
+
+func (rr reportRepo) Summary(ctx context.Context, r entity.ReportRange) (*entity.SalesSummary, error) {
+	var summary entity.SalesSummary
+	err := rr.sales(ctx, r).
+		Select(salesTotals + ", COALESCE(SUM(transaction_histories.discount), 0) AS discount, " +
+			"COALESCE(SUM(transaction_histories.tax), 0) AS tax, COALESCE(SUM(transaction_histories.shipping_cost), 0) AS shipping").
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"e-commerce/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReportRepoMock struct {
+	mock.Mock
+}
+
+func (rrm *ReportRepoMock) RevenueByPeriod(ctx context.Context, r entity.ReportRange, granularity string) ([]entity.RevenuePoint, error) {
+	arguments := rrm.Called(ctx, r, granularity)
+	if arguments.Get(0) == nil {
+		return nil, arguments.Error(1)
+	}
+	return arguments.Get(0).([]entity.RevenuePoint), arguments.Error(1)
+}
+
+func (rrm *ReportRepoMock) TopProducts(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error) {
+	return rrm.rankedItems(rrm.Called(ctx, r, by, limit))
+}
+
+func (rrm *ReportRepoMock) TopCategories(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error) {
+	return rrm.rankedItems(rrm.Called(ctx, r, by, limit))
+}
+
+func (rrm *ReportRepoMock) TopCustomers(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error) {
+	return rrm.rankedItems(rrm.Called(ctx, r, by, limit))
+}
+
+func (rrm *ReportRepoMock) rankedItems(arguments mock.Arguments) ([]entity.RankedItem, error) {
+	if arguments.Get(0) == nil {
+		return nil, arguments.Error(1)
+	}
+	return arguments.Get(0).([]entity.RankedItem), arguments.Error(1)
+}
+
+func (rrm *ReportRepoMock) Summary(ctx context.Context, r entity.ReportRange) (*entity.SalesSummary, error) {
+	arguments := rrm.Called(ctx, r)
+	if arguments.Get(0) == nil {
+		return nil, arguments.Error(1)
+	}
+	return arguments.Get(0).(*entity.SalesSummary), arguments.Error(1)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"e-commerce/entity"
+	"e-commerce/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type reservationRepo struct {
+	db *gorm.DB
+}
+
+func NewReservationRepo(db *gorm.DB) ReservationRepo {
+	return reservationRepo{db: db}
+}
+
+func (rr reservationRepo) ExpireBefore(ctx context.Context, now time.Time) (int64, error) {
+	result := rr.db.WithContext(ctx).Model(&models.StockReservation{}).
+		Where("status = ? AND expires_at <= ?", entity.ReservationActive, now).
+		Update("status", entity.ReservationExpired)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReservationRepoMock struct {
+	mock.Mock
+}
+
+func (rrm *ReservationRepoMock) ExpireBefore(ctx context.Context, now time.Time) (int64, error) {
+	arguments := rrm.Called(ctx, now)
+	return arguments.Get(0).(int64), arguments.Error(1)
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"e-commerce/entity"
+	"e-commerce/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type stockAlertRepo struct {
+	db *gorm.DB
+}
+
+func NewStockAlertRepo(db *gorm.DB) StockAlertRepo {
+	return stockAlertRepo{db: db}
+}
+
+func (sar stockAlertRepo) FindPendingEvents(ctx context.Context, limit int) ([]entity.StockEvent, error) {
+	var records []models.StockEvent
+	if err := sar.db.WithContext(ctx).Where("dispatched_at IS NULL AND attempts < ?", entity.MaxStockEventAttempts).Order("id").Limit(limit).Find(&records).Error; err != nil {
+		return nil, err
+	}
+	events := make([]entity.StockEvent, 0, len(records))
+	for _, record := range records {
+		events = append(events, entity.StockEvent{
+			ID:        record.ID,
+			ProductID: record.ProductID,
+			VariantID: record.VariantID,
+			Type:      record.Type,
+			Stock:     record.Stock,
+			Threshold: record.Threshold,
+			Attempts:  record.Attempts,
+			CreatedAt: record.CreatedAt,
+		})
+	}
+	return events, nil
+}
+
+// ClaimEvent marks an undelivered event as being dispatched at at. It
+// reports false when another dispatcher claimed it after staleBefore or it
+// has been delivered meanwhile.
+func (sar stockAlertRepo) ClaimEvent(ctx context.Context, eventID uint, at, staleBefore time.Time) (bool, error) {
+	result := sar.db.WithContext(ctx).Model(&models.StockEvent{}).
+		Where("id = ? AND dispatched_at IS NULL AND (dispatching_at IS NULL OR dispatching_at < ?)", eventID, staleBefore).
+		Update("dispatching_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (sar stockAlertRepo) MarkEventDispatched(ctx context.Context, eventID uint, at time.Time) error {
+	return sar.db.WithContext(ctx).Model(&models.StockEvent{}).Where("id = ?", eventID).Update("dispatched_at", at).Error
+}
+
+func (sar stockAlertRepo) MarkEventFailed(ctx context.Context, eventID uint, reason string) error {
+	return sar.db.WithContext(ctx).Model(&models.StockEvent{}).Where("id = ?", eventID).
+		Updates(map[string]interface{}{"attempts": gorm.Expr("attempts + 1"), "last_error": reason, "dispatching_at": nil}).Error
+}
+
+func (sar stockAlertRepo) FindWaitingSubscriptions(ctx context.Context, productID uint, variantID *uint) ([]entity.RestockSubscription, error) {
+	query := sar.db.WithContext(ctx).Preload("User").Where("product_id = ? AND notified_at IS NULL", productID)
+	if variantID != nil {
+		query = query.Where("variant_id = ?", *variantID)
+	} else {
+		query = query.Where("variant_id IS NULL")
+	}
+	var records []models.RestockSubscription
+	if err := query.Order("id").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	subscriptions := make([]entity.RestockSubscription, 0, len(records))
+	for _, record := range records {
+		subscriptions = append(subscriptions, entity.RestockSubscription{
+			ID:        record.ID,
+			UserID:    record.UserID,
+			Email:     record.User.Email,
+			FullName:  record.User.FullName,
+			ProductID: record.ProductID,
+			VariantID: record.VariantID,
+		})
+	}
+	return subscriptions, nil
+}
+
+func (sar stockAlertRepo) MarkSubscriptionNotified(ctx context.Context, subscriptionID uint, at time.Time) error {
+	return sar.db.WithContext(ctx).Model(&models.RestockSubscription{}).Where("id = ?", subscriptionID).Update("notified_at", at).Error
+}
+
+func (sar stockAlertRepo) FindProductTitle(ctx context.Context, productID uint) (string, error) {
+	// The product may have been deleted since the event was recorded
+	var product models.Product
+	if err := sar.db.WithContext(ctx).Unscoped().Select("title").First(&product, productID).Error; err != nil {
+		return "", err
+	}
+	return product.Title, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"e-commerce/entity"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type StockAlertRepoMock struct {
+	mock.Mock
+}
+
+func (sarm *StockAlertRepoMock) FindPendingEvents(ctx context.Context, limit int) ([]entity.StockEvent, error) {
+	arguments := sarm.Called(ctx, limit)
+	if arguments.Get(0) == nil {
+		return nil, arguments.Error(1)
+	}
+	events := arguments.Get(0).([]entity.StockEvent)
+	return events, arguments.Error(1)
+}
+
+func (sarm *StockAlertRepoMock) ClaimEvent(ctx context.Context, eventID uint, at, staleBefore time.Time) (bool, error) {
+	arguments := sarm.Called(ctx, eventID, at, staleBefore)
+	return arguments.Bool(0), arguments.Error(1)
+}
+
+func (sarm *StockAlertRepoMock) MarkEventDispatched(ctx context.Context, eventID uint, at time.Time) error {
+	arguments := sarm.Called(ctx, eventID, at)
+	return arguments.Error(0)
+}
+
+func (sarm *StockAlertRepoMock) MarkEventFailed(ctx context.Context, eventID uint, reason string) error {
+	arguments := sarm.Called(ctx, eventID, reason)
+	return arguments.Error(0)
+}
+
+func (sarm *StockAlertRepoMock) FindWaitingSubscriptions(ctx context.Context, productID uint, variantID *uint) ([]entity.RestockSubscription, error) {
+	arguments := sarm.Called(ctx, productID, variantID)
+	if arguments.Get(0) == nil {
+		return nil, arguments.Error(1)
+	}
+	subscriptions := arguments.Get(0).([]entity.RestockSubscription)
+	return subscriptions, arguments.Error(1)
+}
+
+func (sarm *StockAlertRepoMock) MarkSubscriptionNotified(ctx context.Context, subscriptionID uint, at time.Time) error {
+	arguments := sarm.Called(ctx, subscriptionID, at)
+	return arguments.Error(0)
+}
+
+func (sarm *StockAlertRepoMock) FindProductTitle(ctx context.Context, productID uint) (string, error) {
+	arguments := sarm.Called(ctx, productID)
+	return arguments.String(0), arguments.Error(1)
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"e-commerce/entity"
+	"e-commerce/models"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+type transactionRepo struct {
+	db *gorm.DB
+}
+
+func NewTransactionRepo(db *gorm.DB) TransactionRepo {
+	return transactionRepo{db: db}
+}
+
+func (tr transactionRepo) CreateTransactionHistory(ctx context.Context, transaction entity.TransactionHistory) error {
+	return tr.db.WithContext(ctx).Create(&models.TransactionHistory{
+		ProductID:  transaction.ProductID,
+		VariantID:  transaction.VariantID,
+		UserID:     transaction.UserID,
+		Quantity:   transaction.Quantity,
+		TotalPrice: transaction.TotalPrice,
+	}).Error
+}
+
+func (tr transactionRepo) GetTransactionHistoryByUserID(ctx context.Context, userID uint) ([]entity.TransactionHistory, error) {
+	var records []models.TransactionHistory
+	if err := tr.withProduct(ctx).Where("user_id = ?", userID).Order("id").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return transactionEntities(records), nil
+}
+
+func (tr transactionRepo) GetAllTransactionHistory(ctx context.Context) ([]entity.TransactionHistory, error) {
+	var records []models.TransactionHistory
+	if err := tr.withProduct(ctx).Order("id").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return transactionEntities(records), nil
+}
+
+func (tr transactionRepo) FindTransactions(ctx context.Context, filter entity.TransactionFilter) ([]entity.TransactionHistory, int64, error) {
+	db := tr.db.WithContext(ctx)
+	query := db.Model(&models.TransactionHistory{})
+	if filter.UserID != 0 {
+		query = query.Where("transaction_histories.user_id = ?", filter.UserID)
+	}
+	if filter.ProductID != 0 {
+		query = query.Where("transaction_histories.product_id = ?", filter.ProductID)
+	}
+	if filter.CategoryID != 0 {
+		// Unscoped so transactions of deleted products still match
+		query = query.Where("transaction_histories.product_id IN (?)",
+			db.Unscoped().Model(&models.Product{}).Select("id").Where("category_id = ?", filter.CategoryID))
+	}
+	if filter.From != nil {
+		query = query.Where("transaction_histories.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("transaction_histories.created_at < ?", *filter.To)
+	}
+	if filter.MinTotal != nil {
+		query = query.Where("transaction_histories.total_price >= ?", *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		query = query.Where("transaction_histories.total_price <= ?", *filter.MaxTotal)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Sort is checked against services.TransactionSortColumns
+	order := "transaction_histories." + filter.Sort
+	if filter.Descending {
+		order += " DESC"
+	}
+	var records []models.TransactionHistory
+	if err := query.Scopes(tr.preloadProduct).
+		Order(order).Order("transaction_histories.id DESC").
+		Offset((filter.Page - 1) * filter.PageSize).Limit(filter.PageSize).
+		Find(&records).Error; err != nil {
+		return nil, 0, err
+	}
+	return transactionEntities(records), total, nil
+}
+
+func (tr transactionRepo) FindTransactionByID(ctx context.Context, id uint) (*entity.TransactionHistory, error) {
+	var record models.TransactionHistory
+	if err := tr.withProduct(ctx).First(&record, id).Error; err != nil {
+		return nil, err
+	}
+	transaction := transactionEntity(record)
+	return &transaction, nil
+}
+
+func (tr transactionRepo) withProduct(ctx context.Context) *gorm.DB {
+	return tr.preloadProduct(tr.db.WithContext(ctx))
+}
+
+// preloadProduct loads the product and variant even when they have been
+// deleted since the purchase.
+func (tr transactionRepo) preloadProduct(db *gorm.DB) *gorm.DB {
+	return db.Preload("Product", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Variant", func(db *gorm.DB) *gorm.DB { return db.Unscoped() })
+}
+
+func transactionEntities(records []models.TransactionHistory) []entity.TransactionHistory {
+	transactions := make([]entity.TransactionHistory, 0, len(records))
+	for _, record := range records {
+		transactions = append(transactions, transactionEntity(record))
+	}
+	return transactions
+}
+
+func transactionEntity(record models.TransactionHistory) entity.TransactionHistory {
+	transaction := entity.TransactionHistory{
+		ID:           strconv.FormatUint(uint64(record.ID), 10),
+		ProductID:    record.ProductID,
+		VariantID:    record.VariantID,
+		UserID:       record.UserID,
+		Quantity:     record.Quantity,
+		Subtotal:     record.Subtotal,
+		Discount:     record.Discount,
+		CouponID:     record.CouponID,
+		TaxName:      record.TaxName,
+		TaxRate:      record.TaxRate,
+		TaxBase:      record.TaxBase,
+		Tax:          record.Tax,
+		TaxIncluded:  record.TaxIncluded,
+		ShippingCost: record.ShippingCost,
+		AddressID:    record.AddressID,
+		ShippingAddress: entity.ShippingAddress{
+			RecipientName: record.ShippingAddress.RecipientName,
+			Phone:         record.ShippingAddress.Phone,
+			Street:        record.ShippingAddress.Street,
+			City:          record.ShippingAddress.City,
+			Province:      record.ShippingAddress.Province,
+			PostalCode:    record.ShippingAddress.PostalCode,
+		},
+		TotalPrice: record.TotalPrice,
+		CreatedAt:  record.CreatedAt,
+		Product: entity.Product{
+			ID:         strconv.FormatUint(uint64(record.Product.ID), 10),
+			SKU:        record.Product.SKU,
+			Title:      record.Product.Title,
+			Price:      record.Product.Price,
+			CategoryID: int(record.Product.CategoryID),
+			Weight:     record.Product.Weight,
+		},
+	}
+	if record.Variant != nil {
+		transaction.Variant = &entity.ProductVariant{
+			ID:        record.Variant.ID,
+			ProductID: record.Variant.ProductID,
+			SKU:       record.Variant.SKU,
+			Size:      record.Variant.Size,
+			Color:     record.Variant.Color,
+			Price:     record.Variant.Price,
+		}
+	}
+	return transaction
+}
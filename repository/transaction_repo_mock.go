@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"e-commerce/entity"
 
 	"github.com/stretchr/testify/mock"
@@ -10,15 +11,32 @@ type TransactionRepoMock struct {
 	mock.Mock
 }
 
-func (trm *TransactionRepoMock) CreateTransactionHistory(transaction entity.TransactionHistory) error {
-	args := trm.Called(transaction)
+func (trm *TransactionRepoMock) CreateTransactionHistory(ctx context.Context, transaction entity.TransactionHistory) error {
+	args := trm.Called(ctx, transaction)
 	return args.Error(0)
 }
-func (trm *TransactionRepoMock) GetTransactionHistoryByUserID(userID uint) ([]entity.TransactionHistory, error) {
-	args := trm.Called(userID)
+func (trm *TransactionRepoMock) GetTransactionHistoryByUserID(ctx context.Context, userID uint) ([]entity.TransactionHistory, error) {
+	args := trm.Called(ctx, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	transactions := args.Get(0).([]entity.TransactionHistory)
 	return transactions, args.Error(1)
 }
+
+func (trm *TransactionRepoMock) FindTransactions(ctx context.Context, filter entity.TransactionFilter) ([]entity.TransactionHistory, int64, error) {
+	args := trm.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	transactions := args.Get(0).([]entity.TransactionHistory)
+	return transactions, args.Get(1).(int64), args.Error(2)
+}
+func (trm *TransactionRepoMock) FindTransactionByID(ctx context.Context, id uint) (*entity.TransactionHistory, error) {
+	args := trm.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	transaction := args.Get(0).(*entity.TransactionHistory)
+	return transaction, args.Error(1)
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"e-commerce/entity"
 
 	"github.com/stretchr/testify/mock"
@@ -10,13 +11,13 @@ type UserRepoMock struct {
 	mock.Mock
 }
 
-func (urm *UserRepoMock) Create(user *entity.User) error {
-	arguments := urm.Called(user)
+func (urm *UserRepoMock) Create(ctx context.Context, user *entity.User) error {
+	arguments := urm.Called(ctx, user)
 	return arguments.Error(0)
 }
 
-func (urm *UserRepoMock) FindByEmail(email string) (*entity.User, error) {
-	arguments := urm.Called(email)
+func (urm *UserRepoMock) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	arguments := urm.Called(ctx, email)
 	if arguments.Get(0) == nil {
 		return nil, arguments.Error(1)
 	}
@@ -24,12 +25,12 @@ func (urm *UserRepoMock) FindByEmail(email string) (*entity.User, error) {
 	return user, arguments.Error(1)
 }
 
-func (crm *UserRepoMock) UpdateBalance(user *entity.User) error {
-	arguments := crm.Mock.Called(user)
+func (crm *UserRepoMock) UpdateBalance(ctx context.Context, user *entity.User) error {
+	arguments := crm.Mock.Called(ctx, user)
 	return arguments.Error(0)
 }
-func (trm *TransactionRepoMock) GetAllTransactionHistory() ([]entity.TransactionHistory, error) {
-	args := trm.Called()
+func (trm *TransactionRepoMock) GetAllTransactionHistory(ctx context.Context) ([]entity.TransactionHistory, error) {
+	args := trm.Called(ctx)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -0,0 +1,42 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation announces that an API version is going away.
+type Deprecation struct {
+	// Since is when the version was deprecated.
+	Since time.Time
+	// Sunset is when it stops being served, zero until that is decided.
+	Sunset time.Time
+	// Successor is the prefix of the version replacing it, e.g. "/api/v2".
+	Successor string
+}
+
+// Deprecated marks responses of the version mounted at prefix with the
+// Deprecation (RFC 9745) and Sunset (RFC 8594) headers, and links each
+// request to the same path in the successor version.
+func Deprecated(prefix string, d Deprecation) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(d.Since.Unix(), 10)
+	sunset := ""
+	if !d.Sunset.IsZero() {
+		sunset = d.Sunset.UTC().Format(http.TimeFormat)
+	}
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if sunset != "" {
+			c.Header("Sunset", sunset)
+		}
+		if d.Successor != "" {
+			successor := d.Successor + strings.TrimPrefix(c.Request.URL.Path, prefix)
+			c.Writer.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+// Package router builds the gin engine. Probes, metrics, the API docs and
+// signed image URLs are served at the root; the API itself is served under
+// /api/v1 and, while clients migrate, /api/v2.
+package router
+
+import (
+	"e-commerce/docs"
+	"e-commerce/handlers"
+	"e-commerce/i18n"
+	"e-commerce/invoice"
+	"e-commerce/logging"
+	"e-commerce/metrics"
+	"e-commerce/money"
+	"e-commerce/services"
+	"e-commerce/storage"
+	"e-commerce/tracing"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	swaggerfiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
+)
+
+// Prefixes the API versions are mounted at.
+const (
+	V1 = "/api/v1"
+	V2 = "/api/v2"
+)
+
+// Dependencies are what the handlers are built from. main wires them from
+// config.
+type Dependencies struct {
+	DB             *gorm.DB
+	Logger         *slog.Logger
+	Readiness      *handlers.Readiness
+	Models         []interface{}
+	Store          storage.BlobStore
+	Signer         storage.URLSigner
+	ImageLimits    services.ImageLimits
+	Rates          money.RateProvider
+	Jobs           services.JobService
+	Tax            services.TaxConfig
+	Shipping       services.ShippingCalculator
+	InvoiceSeller  invoice.Party
+	ReservationTTL time.Duration
+	MetricsToken   string
+	DefaultLocale  string
+	RequestTimeout time.Duration
+}
+
+// Options choose the API versions served.
+type Options struct {
+	// V2 serves /api/v2 next to /api/v1.
+	V2 bool
+	// V1Deprecation, when set, announces on every /api/v1 response that v1
+	// is going away.
+	V1Deprecation *Deprecation
+}
+
+// New returns the engine serving every route.
+func New(deps Dependencies, opts Options) *gin.Engine {
+	r := gin.New()
+	// Probes are registered before the middleware so they aren't logged,
+	// traced or counted
+	r.GET("/healthz", handlers.Healthz())
+	r.GET("/readyz", handlers.Readyz(deps.DB, deps.Readiness, deps.Models))
+	r.Use(
+		logging.Middleware(deps.Logger),
+		tracing.Middleware(),
+		metrics.Middleware(),
+		gin.Recovery(),
+		i18n.Middleware(deps.DefaultLocale),
+		handlers.RequestTimeout(deps.RequestTimeout),
+	)
+
+	r.GET("/metrics", metrics.Handler(deps.MetricsToken))
+	docs.SwaggerInfo.BasePath = V1
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+	// Image URLs are signed and handed out by the API, so they stay the
+	// same whichever version issued them
+	r.GET("/images/*key", handlers.ServeImage(deps.Store, deps.Signer))
+
+	v1 := r.Group(V1)
+	if opts.V1Deprecation != nil {
+		v1.Use(Deprecated(V1, *opts.V1Deprecation))
+	}
+	registerV1(v1, deps)
+	if opts.V2 {
+		registerV2(r.Group(V2), deps)
+	}
+	return r
+}
@@ -0,0 +1,78 @@
+package router
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEngine(opts Options) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return New(Dependencies{
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		DefaultLocale:  "en",
+		RequestTimeout: time.Second,
+	}, opts)
+}
+
+func routes(r *gin.Engine) map[string]bool {
+	registered := map[string]bool{}
+	for _, route := range r.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	return registered
+}
+
+func TestNewMountsV1(t *testing.T) {
+	registered := routes(newTestEngine(Options{}))
+
+	assert.True(t, registered["GET /api/v1/products"])
+	assert.True(t, registered["POST /api/v1/users/login"])
+	assert.True(t, registered["GET /api/v1/transactions/:transactionId/invoice"])
+	assert.True(t, registered["GET /healthz"])
+	assert.True(t, registered["GET /images/*key"])
+	assert.False(t, registered["GET /products"], "API routes are only served under a version")
+	assert.False(t, registered["GET /api/v2/products"], "v2 is opt-in")
+}
+
+func TestNewMountsV2AlongsideV1(t *testing.T) {
+	registered := routes(newTestEngine(Options{V2: true}))
+
+	assert.True(t, registered["GET /api/v2/products"])
+	for route := range registered {
+		if v2Route := strings.Replace(route, V1, V2, 1); v2Route != route {
+			assert.True(t, registered[v2Route], "v2 is missing %s", v2Route)
+		}
+	}
+}
+
+func TestDeprecatedV1(t *testing.T) {
+	r := newTestEngine(Options{
+		V2: true,
+		V1Deprecation: &Deprecation{
+			Since:     time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+			Sunset:    time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC),
+			Successor: V2,
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/addresses", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "@1788220800", rec.Header().Get("Deprecation"))
+	assert.Equal(t, "Mon, 01 Mar 2027 00:00:00 GMT", rec.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v2/addresses>; rel="successor-version"`, rec.Header().Get("Link"))
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/addresses", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Link"))
+}
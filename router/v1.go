@@ -0,0 +1,116 @@
+package router
+
+import (
+	"e-commerce/auth"
+	"e-commerce/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerV1 mounts every resource of v1 on api. Each resource is a group
+// with its own middleware: customers need to be logged in and admin
+// endpoints need the admin role.
+func registerV1(api *gin.RouterGroup, deps Dependencies) {
+	users(api.Group("/users"), deps)
+	categories(api.Group("/categories", auth.AuthorizationMiddleware()), deps)
+	products(api.Group("/products"), deps)
+	coupons(api.Group("/coupons", auth.AuthorizationMiddleware()), deps)
+	addresses(api.Group("/addresses", auth.AuthenticationMiddleware()), deps)
+	transactions(api.Group("/transactions"), deps)
+	reservations(api.Group("/reservations", auth.AuthenticationMiddleware()), deps)
+	reports(api.Group("/reports", auth.AuthorizationMiddleware()), deps)
+	jobs(api.Group("/jobs", auth.AuthorizationMiddleware()), deps)
+}
+
+func users(g *gin.RouterGroup, deps Dependencies) {
+	g.POST("/register", handlers.Register(deps.DB))
+	g.POST("/login", handlers.Login(deps.DB))
+	g.PATCH("/topup", auth.AuthenticationMiddleware(), handlers.UpdateUserBalance(deps.DB))
+}
+
+func categories(g *gin.RouterGroup, deps Dependencies) {
+	g.GET("", handlers.GetCategories(deps.DB))
+	g.POST("", handlers.CreateCategory(deps.DB))
+	g.PATCH("/:categoryId", handlers.UpdateCategory(deps.DB))
+	g.DELETE("/:categoryId", handlers.DeleteCategory(deps.DB))
+}
+
+func products(g *gin.RouterGroup, deps Dependencies) {
+	db := deps.DB
+	customer := g.Group("", auth.AuthenticationMiddleware())
+	customer.GET("", handlers.GetProducts(db, deps.Rates))
+	customer.GET("/:productId/variants", handlers.GetVariants(db))
+	customer.POST("/:productId/restock-subscriptions", handlers.SubscribeRestock(db))
+	customer.DELETE("/:productId/restock-subscriptions", handlers.UnsubscribeRestock(db))
+	customer.GET("/:productId/price-history", handlers.GetPriceHistory(db))
+	customer.GET("/:productId/images", handlers.GetProductImages(db, deps.Signer))
+
+	admin := g.Group("", auth.AuthorizationMiddleware())
+	admin.GET("/low-stock", handlers.GetLowStockReport(db))
+	admin.GET("/export", handlers.ExportProducts(db))
+	admin.POST("/import", handlers.ImportProducts(db))
+	admin.POST("", handlers.CreateProduct(db))
+	admin.PUT("/:productId", handlers.UpdateProduct(db))
+	admin.DELETE("/:productId", handlers.DeleteProduct(db))
+	admin.POST("/:productId/variants", handlers.CreateVariant(db))
+	admin.POST("/:productId/variants/matrix", handlers.CreateVariantMatrix(db))
+	admin.PUT("/:productId/variants/:variantId", handlers.UpdateVariant(db))
+	admin.DELETE("/:productId/variants/:variantId", handlers.DeleteVariant(db))
+	admin.POST("/:productId/stock-adjustments", handlers.CreateStockAdjustment(db))
+	admin.GET("/:productId/stock-history", handlers.GetStockHistory(db))
+	admin.PUT("/:productId/prices", handlers.SetProductPrices(db))
+	admin.GET("/:productId/price-schedules", handlers.GetPriceSchedules(db))
+	admin.POST("/:productId/price-schedules", handlers.CreatePriceSchedule(db))
+	admin.DELETE("/:productId/price-schedules/:scheduleId", handlers.CancelPriceSchedule(db))
+	admin.POST("/:productId/images", handlers.UploadProductImage(db, deps.Store, deps.Signer, deps.ImageLimits))
+	admin.PUT("/:productId/images/order", handlers.ReorderProductImages(db))
+	admin.DELETE("/:productId/images/:imageId", handlers.DeleteProductImage(db, deps.Store))
+}
+
+func coupons(g *gin.RouterGroup, deps Dependencies) {
+	g.GET("", handlers.GetCoupons(deps.DB))
+	g.POST("", handlers.CreateCoupon(deps.DB))
+	g.PUT("/:couponId", handlers.UpdateCoupon(deps.DB))
+	g.DELETE("/:couponId", handlers.DeleteCoupon(deps.DB))
+	g.GET("/:couponId/stats", handlers.GetCouponStats(deps.DB))
+}
+
+func addresses(g *gin.RouterGroup, deps Dependencies) {
+	g.GET("", handlers.GetMyAddresses(deps.DB))
+	g.POST("", handlers.CreateAddress(deps.DB))
+	g.PUT("/:addressId", handlers.UpdateAddress(deps.DB))
+	g.DELETE("/:addressId", handlers.DeleteAddress(deps.DB))
+}
+
+func transactions(g *gin.RouterGroup, deps Dependencies) {
+	customer := g.Group("", auth.AuthenticationMiddleware())
+	customer.POST("", handlers.CreateTransaction(deps.DB, deps.Tax, deps.Shipping))
+	customer.GET("/my-transactions", handlers.GetMyTransaction(deps.DB))
+	customer.GET("/:transactionId", handlers.GetTransactionDetail(deps.DB))
+	customer.GET("/:transactionId/invoice", handlers.GetInvoice(deps.DB, deps.InvoiceSeller))
+
+	admin := g.Group("", auth.AuthorizationMiddleware())
+	admin.GET("/user-transactions", handlers.GetTransaction(deps.DB))
+}
+
+func reservations(g *gin.RouterGroup, deps Dependencies) {
+	g.GET("", handlers.GetMyReservations(deps.DB))
+	g.POST("", handlers.CreateReservation(deps.DB, deps.ReservationTTL))
+	g.DELETE("/:reservationId", handlers.ReleaseReservation(deps.DB))
+}
+
+func reports(g *gin.RouterGroup, deps Dependencies) {
+	g.GET("/revenue", handlers.GetRevenueReport(deps.DB))
+	g.GET("/summary", handlers.GetSalesSummaryReport(deps.DB))
+	g.GET("/top-products", handlers.GetTopProductsReport(deps.DB))
+	g.GET("/top-categories", handlers.GetTopCategoriesReport(deps.DB))
+	g.GET("/top-customers", handlers.GetTopCustomersReport(deps.DB))
+}
+
+func jobs(g *gin.RouterGroup, deps Dependencies) {
+	g.GET("", handlers.GetJobs(deps.DB))
+	g.POST("", handlers.CreateJob(deps.DB, deps.Jobs))
+	g.PUT("/:jobId", handlers.UpdateJob(deps.DB, deps.Jobs))
+	g.DELETE("/:jobId", handlers.DeleteJob(deps.DB))
+	g.POST("/:jobId/run", handlers.RunJob(deps.DB))
+}
@@ -0,0 +1,10 @@
+package router
+
+import "github.com/gin-gonic/gin"
+
+// registerV2 mounts v2 on api. It starts out as a copy of v1 so clients can
+// switch prefixes before any contract changes; an endpoint that changes is
+// registered here with its new handler instead of v1's.
+func registerV2(api *gin.RouterGroup, deps Dependencies) {
+	registerV1(api, deps)
+}
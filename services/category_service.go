@@ -1,9 +1,11 @@
 package services
 
 import (
+	"context"
+	"e-commerce/apperror"
 	"e-commerce/entity"
 	"e-commerce/repository"
-	"errors"
+	"fmt"
 )
 
 type CategoryService struct {
@@ -13,54 +15,91 @@ type CategoryInput struct {
 	Type string
 }
 
-func (cs CategoryService) FindAllCategories() ([]entity.Category, error) {
-	categories := cs.Repository.FindAll()
+// Delete modes decide what happens to the products of a deleted category.
+const (
+	DeleteModeRestrict = "restrict"
+	DeleteModeCascade  = "cascade"
+	DeleteModeReassign = "reassign"
+)
+
+type DeleteCategoryInput struct {
+	Mode             string
+	TargetCategoryID uint
+}
+
+func (cs CategoryService) FindAllCategories(ctx context.Context) ([]entity.Category, error) {
+	categories := cs.Repository.FindAll(ctx)
 	if len(categories) == 0 {
-		return nil, errors.New("categories not found")
+		return nil, apperror.NotFound("categories_not_found", "categories not found")
 	}
 	return categories, nil
 }
 
-func (cs CategoryService) CreateCategory(category entity.Category) error {
+func (cs CategoryService) CreateCategory(ctx context.Context, category entity.Category) error {
 
 	if category.Type == "" {
-		return errors.New("category type cannot be empty")
+		return apperror.Validation("category_type_required", "category type cannot be empty")
 	}
 
-	return cs.Repository.Create(category)
+	return cs.Repository.Create(ctx, category)
 }
-func (cs CategoryService) UpdateCategory(userInput CategoryInput) (*entity.Category, error) {
-	existingCategory, err := cs.Repository.FindByType(userInput.Type)
+func (cs CategoryService) UpdateCategory(ctx context.Context, userInput CategoryInput) (*entity.Category, error) {
+	existingCategory, err := cs.Repository.FindByType(ctx, userInput.Type)
 	if err != nil {
 		return nil, err
 	}
 	if existingCategory == nil {
-		return nil, errors.New("category not found")
+		return nil, apperror.NotFound("category_not_found", "category not found")
 	}
 
 	existingCategory.Type = userInput.Type
 
-	err = cs.Repository.Update(existingCategory)
+	err = cs.Repository.Update(ctx, existingCategory)
 	if err != nil {
 		return nil, err
 	}
 
 	return existingCategory, nil
 }
-func (cs CategoryService) DeleteCategory(categoryID uint) error {
-	existingCategory, err := cs.Repository.FindByID(categoryID)
+func (cs CategoryService) DeleteCategory(ctx context.Context, categoryID uint, input DeleteCategoryInput) error {
+	existingCategory, err := cs.Repository.FindByID(ctx, categoryID)
 	if err != nil {
 		return err
 	}
 
 	if existingCategory == nil {
-		return errors.New("category not found")
+		return apperror.NotFound("category_not_found", "category not found")
 	}
 
-	err = cs.Repository.Delete(existingCategory)
+	var deletion entity.CategoryDeletion
+	switch input.Mode {
+	case "", DeleteModeRestrict:
+	case DeleteModeCascade:
+		deletion.Cascade = true
+	case DeleteModeReassign:
+		if input.TargetCategoryID == 0 || input.TargetCategoryID == categoryID {
+			return apperror.Validation("invalid_target_category", "invalid target category")
+		}
+		targetCategory, err := cs.Repository.FindByID(ctx, input.TargetCategoryID)
+		if err != nil {
+			return err
+		}
+		if targetCategory == nil {
+			return apperror.NotFound("target_category_not_found", "target category not found")
+		}
+		deletion.ReassignTo = input.TargetCategoryID
+	default:
+		return apperror.Validation("invalid_delete_mode", "invalid delete mode")
+	}
+
+	remaining, err := cs.Repository.Delete(ctx, existingCategory, deletion)
 	if err != nil {
 		return err
 	}
+	if remaining > 0 {
+		message := fmt.Sprintf("category still has %d products, delete them with mode=cascade or move them with mode=reassign", remaining)
+		return apperror.Conflict("category_has_products", message).With("count", remaining)
+	}
 
 	return nil
 }
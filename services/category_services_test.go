@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"e-commerce/apperror"
 	"e-commerce/entity"
 	"e-commerce/repository"
 	"testing"
@@ -23,11 +25,11 @@ func TestCategoryServiceFindAllCategories(t *testing.T) {
 		{ID: 2, Type: "Clothing", SoldProductAmount: 30},
 	}
 
-	categoryRepo.On("FindAll").Return(dummyCategories)
+	categoryRepo.On("FindAll", mock.Anything).Return(dummyCategories)
 
 	categoryService := CategoryService{Repository: categoryRepo}
 
-	resultCategories, err := categoryService.FindAllCategories()
+	resultCategories, err := categoryService.FindAllCategories(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, dummyCategories, resultCategories)
@@ -45,16 +47,16 @@ func TestCategoryServiceCreateCategory(t *testing.T) {
 		SoldProductAmount: 0,
 	}
 
-	categoryRepo.On("Create", dummyCategory).Return(nil)
+	categoryRepo.On("Create", mock.Anything, dummyCategory).Return(nil)
 
 	categoryService := CategoryService{Repository: categoryRepo}
 
-	err := categoryService.CreateCategory(dummyCategory)
+	err := categoryService.CreateCategory(context.Background(), dummyCategory)
 
 	assert.NoError(t, err)
 
 	categoryRepo.AssertExpectations(t)
-	categoryRepo.Mock.AssertCalled(t, "Create", dummyCategory)
+	categoryRepo.Mock.AssertCalled(t, "Create", mock.Anything, dummyCategory)
 }
 func TestCategoryServiceUpdateCategory(t *testing.T) {
 
@@ -66,22 +68,22 @@ func TestCategoryServiceUpdateCategory(t *testing.T) {
 
 	categoryRepo := &repository.CategoryRepoMock{}
 
-	categoryRepo.On("FindByType", "Furniture").Return(dummyCategory, nil)
+	categoryRepo.On("FindByType", mock.Anything, "Furniture").Return(dummyCategory, nil)
 
-	categoryRepo.On("Update", dummyCategory).Return(nil)
+	categoryRepo.On("Update", mock.Anything, dummyCategory).Return(nil)
 
 	categoryService := CategoryService{Repository: categoryRepo}
 
 	userInput := CategoryInput{Type: "Furniture"}
-	updatedCategory, err := categoryService.UpdateCategory(userInput)
+	updatedCategory, err := categoryService.UpdateCategory(context.Background(), userInput)
 
 	assert.NoError(t, err)
 
 	assert.Equal(t, userInput.Type, updatedCategory.Type)
 
 	categoryRepo.AssertExpectations(t)
-	categoryRepo.Mock.AssertCalled(t, "FindByType", "Furniture")
-	categoryRepo.Mock.AssertCalled(t, "Update", dummyCategory)
+	categoryRepo.Mock.AssertCalled(t, "FindByType", mock.Anything, "Furniture")
+	categoryRepo.Mock.AssertCalled(t, "Update", mock.Anything, dummyCategory)
 }
 func TestCategoryServiceDeleteCategory(t *testing.T) {
 
@@ -93,17 +95,74 @@ func TestCategoryServiceDeleteCategory(t *testing.T) {
 
 	categoryRepo := &repository.CategoryRepoMock{}
 
-	categoryRepo.On("FindByID", uint(1)).Return(dummyCategory, nil)
+	categoryRepo.On("FindByID", mock.Anything, uint(1)).Return(dummyCategory, nil)
 
-	categoryRepo.On("Delete", dummyCategory).Return(nil)
+	categoryRepo.On("Delete", mock.Anything, dummyCategory, entity.CategoryDeletion{}).Return(int64(0), nil)
 
 	categoryService := CategoryService{Repository: categoryRepo}
 
-	err := categoryService.DeleteCategory(1)
+	err := categoryService.DeleteCategory(context.Background(), 1, DeleteCategoryInput{})
+
+	assert.NoError(t, err)
+
+	categoryRepo.AssertExpectations(t)
+	categoryRepo.Mock.AssertCalled(t, "FindByID", mock.Anything, uint(1))
+	categoryRepo.Mock.AssertCalled(t, "Delete", mock.Anything, dummyCategory, entity.CategoryDeletion{})
+}
+func TestCategoryServiceDeleteCategoryWithProducts(t *testing.T) {
+
+	dummyCategory := &entity.Category{ID: 1, Type: "Electronic"}
+
+	categoryRepo := &repository.CategoryRepoMock{}
+
+	categoryRepo.On("FindByID", mock.Anything, uint(1)).Return(dummyCategory, nil)
+
+	categoryRepo.On("Delete", mock.Anything, dummyCategory, entity.CategoryDeletion{}).Return(int64(2), nil)
+
+	categoryService := CategoryService{Repository: categoryRepo}
+
+	err := categoryService.DeleteCategory(context.Background(), 1, DeleteCategoryInput{Mode: DeleteModeRestrict})
+
+	assertAppError(t, err, apperror.ErrConflict, "category_has_products")
+
+	categoryRepo.AssertExpectations(t)
+}
+func TestCategoryServiceDeleteCategoryCascade(t *testing.T) {
+
+	dummyCategory := &entity.Category{ID: 1, Type: "Electronic"}
+
+	categoryRepo := &repository.CategoryRepoMock{}
+
+	categoryRepo.On("FindByID", mock.Anything, uint(1)).Return(dummyCategory, nil)
+
+	categoryRepo.On("Delete", mock.Anything, dummyCategory, entity.CategoryDeletion{Cascade: true}).Return(int64(0), nil)
+
+	categoryService := CategoryService{Repository: categoryRepo}
+
+	err := categoryService.DeleteCategory(context.Background(), 1, DeleteCategoryInput{Mode: DeleteModeCascade})
+
+	assert.NoError(t, err)
+
+	categoryRepo.AssertExpectations(t)
+}
+func TestCategoryServiceDeleteCategoryReassign(t *testing.T) {
+
+	dummyCategory := &entity.Category{ID: 1, Type: "Electronic"}
+	targetCategory := &entity.Category{ID: 2, Type: "Gadget"}
+
+	categoryRepo := &repository.CategoryRepoMock{}
+
+	categoryRepo.On("FindByID", mock.Anything, uint(1)).Return(dummyCategory, nil)
+
+	categoryRepo.On("FindByID", mock.Anything, uint(2)).Return(targetCategory, nil)
+
+	categoryRepo.On("Delete", mock.Anything, dummyCategory, entity.CategoryDeletion{ReassignTo: 2}).Return(int64(0), nil)
+
+	categoryService := CategoryService{Repository: categoryRepo}
+
+	err := categoryService.DeleteCategory(context.Background(), 1, DeleteCategoryInput{Mode: DeleteModeReassign, TargetCategoryID: 2})
 
 	assert.NoError(t, err)
 
 	categoryRepo.AssertExpectations(t)
-	categoryRepo.Mock.AssertCalled(t, "FindByID", uint(1))
-	categoryRepo.Mock.AssertCalled(t, "Delete", dummyCategory)
 }
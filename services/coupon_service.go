@@ -0,0 +1,107 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/i18n"
+	"e-commerce/money"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PurchaseLine is the part of a purchase a coupon is applied to.
+type PurchaseLine struct {
+	ProductID  uint
+	CategoryID uint
+	Quantity   int
+	UnitPrice  int
+}
+
+func (line PurchaseLine) Subtotal() int {
+	return line.Quantity * line.UnitPrice
+}
+
+// CouponUsage is how often a coupon has been redeemed in total and by the
+// buying user.
+type CouponUsage struct {
+	Total  int64
+	ByUser int64
+}
+
+// NormalizeCouponCode makes coupon codes case-insensitive.
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func ValidateCoupon(coupon entity.Coupon) error {
+	if NormalizeCouponCode(coupon.Code) == "" {
+		return apperror.Validation("coupon_code_required", "coupon code cannot be empty")
+	}
+	switch coupon.Type {
+	case entity.CouponPercentage:
+		if coupon.Value <= 0 || coupon.Value > 100 {
+			return apperror.Validation("invalid_coupon_percentage", "percentage must be between 1 and 100")
+		}
+	case entity.CouponFixedAmount, entity.CouponFreeQuantity:
+		if coupon.Value <= 0 {
+			return apperror.Validation("invalid_coupon_value", "coupon value must be greater than zero")
+		}
+	default:
+		return apperror.Validation("invalid_coupon_type", "invalid coupon type")
+	}
+	if coupon.MinSpend < 0 || coupon.MaxRedemptions < 0 || coupon.MaxPerUser < 0 {
+		return apperror.Validation("invalid_coupon_limits", "coupon limits can't be negative")
+	}
+	if coupon.StartsAt != nil && coupon.EndsAt != nil && !coupon.EndsAt.After(*coupon.StartsAt) {
+		return apperror.Validation("invalid_coupon_period", "coupon must end after it starts")
+	}
+	return nil
+}
+
+// CalculateDiscount checks that coupon can be used for line and returns
+// the discount, which never exceeds the subtotal.
+func CalculateDiscount(coupon entity.Coupon, line PurchaseLine, usage CouponUsage, now time.Time) (int, error) {
+	if !coupon.Active {
+		return 0, apperror.Validation("coupon_inactive", "coupon is not active")
+	}
+	if coupon.StartsAt != nil && now.Before(*coupon.StartsAt) {
+		return 0, apperror.Validation("coupon_not_started", "coupon is not valid yet")
+	}
+	if coupon.EndsAt != nil && !now.Before(*coupon.EndsAt) {
+		return 0, apperror.Validation("coupon_expired", "coupon has expired")
+	}
+	if coupon.MaxRedemptions > 0 && usage.Total >= int64(coupon.MaxRedemptions) {
+		return 0, apperror.Validation("coupon_fully_redeemed", "coupon has been fully redeemed")
+	}
+	if coupon.MaxPerUser > 0 && usage.ByUser >= int64(coupon.MaxPerUser) {
+		return 0, apperror.Validation("coupon_usage_limit_reached", "coupon usage limit reached")
+	}
+	if coupon.ProductID != nil && *coupon.ProductID != line.ProductID {
+		return 0, apperror.Validation("coupon_not_applicable", "coupon does not apply to this product").With("target", i18n.Term("product"))
+	}
+	if coupon.CategoryID != nil && *coupon.CategoryID != line.CategoryID {
+		return 0, apperror.Validation("coupon_not_applicable", "coupon does not apply to this category").With("target", i18n.Term("category"))
+	}
+
+	subtotal := line.Subtotal()
+	if subtotal < coupon.MinSpend {
+		return 0, apperror.Validation("coupon_minimum_spend", fmt.Sprintf("minimum spend for this coupon is %s", money.IDR(coupon.MinSpend))).With("amount", money.IDR(coupon.MinSpend))
+	}
+
+	var discount int
+	switch coupon.Type {
+	case entity.CouponPercentage:
+		discount = subtotal * coupon.Value / 100
+	case entity.CouponFixedAmount:
+		discount = coupon.Value
+	case entity.CouponFreeQuantity:
+		if line.Quantity <= coupon.Value {
+			return 0, apperror.Validation("coupon_minimum_quantity", fmt.Sprintf("buy more than %d to get %d free", coupon.Value, coupon.Value)).With("quantity", coupon.Value)
+		}
+		discount = coupon.Value * line.UnitPrice
+	default:
+		return 0, apperror.Validation("invalid_coupon_type", "invalid coupon type")
+	}
+	return min(discount, subtotal), nil
+}
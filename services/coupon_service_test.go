@@ -0,0 +1,66 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var couponNow = time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+func TestCalculateDiscount(t *testing.T) {
+	line := PurchaseLine{ProductID: 1, CategoryID: 2, Quantity: 3, UnitPrice: 10000}
+
+	discount, err := CalculateDiscount(entity.Coupon{Type: entity.CouponPercentage, Value: 10, Active: true}, line, CouponUsage{}, couponNow)
+	assert.NoError(t, err)
+	assert.Equal(t, 3000, discount)
+
+	discount, err = CalculateDiscount(entity.Coupon{Type: entity.CouponFixedAmount, Value: 50000, Active: true}, line, CouponUsage{}, couponNow)
+	assert.NoError(t, err)
+	assert.Equal(t, 30000, discount)
+
+	discount, err = CalculateDiscount(entity.Coupon{Type: entity.CouponFreeQuantity, Value: 1, Active: true}, line, CouponUsage{}, couponNow)
+	assert.NoError(t, err)
+	assert.Equal(t, 10000, discount)
+}
+
+func TestCalculateDiscountRules(t *testing.T) {
+	line := PurchaseLine{ProductID: 1, CategoryID: 2, Quantity: 1, UnitPrice: 10000}
+	percentage := entity.Coupon{Type: entity.CouponPercentage, Value: 10, Active: true}
+	otherCategory := uint(5)
+	yesterday := couponNow.Add(-24 * time.Hour)
+
+	inactive := percentage
+	inactive.Active = false
+	_, err := CalculateDiscount(inactive, line, CouponUsage{}, couponNow)
+	assertAppError(t, err, apperror.ErrValidation, "coupon_inactive")
+
+	expired := percentage
+	expired.EndsAt = &yesterday
+	_, err = CalculateDiscount(expired, line, CouponUsage{}, couponNow)
+	assertAppError(t, err, apperror.ErrValidation, "coupon_expired")
+
+	limited := percentage
+	limited.MaxRedemptions, limited.MaxPerUser = 100, 1
+	_, err = CalculateDiscount(limited, line, CouponUsage{Total: 100}, couponNow)
+	assertAppError(t, err, apperror.ErrValidation, "coupon_fully_redeemed")
+	_, err = CalculateDiscount(limited, line, CouponUsage{Total: 4, ByUser: 1}, couponNow)
+	assertAppError(t, err, apperror.ErrValidation, "coupon_usage_limit_reached")
+
+	scoped := percentage
+	scoped.CategoryID = &otherCategory
+	_, err = CalculateDiscount(scoped, line, CouponUsage{}, couponNow)
+	assertAppError(t, err, apperror.ErrValidation, "coupon_not_applicable")
+
+	minSpend := percentage
+	minSpend.MinSpend = 20000
+	_, err = CalculateDiscount(minSpend, line, CouponUsage{}, couponNow)
+	assertAppError(t, err, apperror.ErrValidation, "coupon_minimum_spend")
+
+	freeQuantity := entity.Coupon{Type: entity.CouponFreeQuantity, Value: 1, Active: true}
+	_, err = CalculateDiscount(freeQuantity, line, CouponUsage{}, couponNow)
+	assertAppError(t, err, apperror.ErrValidation, "coupon_minimum_quantity")
+}
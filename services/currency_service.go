@@ -0,0 +1,36 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/money"
+	"e-commerce/validation"
+	"strings"
+)
+
+// PriceIn returns a price of base rupiah in currency. A product's own price
+// in that currency wins over converting at the current exchange rate.
+func PriceIn(base int, prices []entity.ProductPrice, currency string, rates money.RateProvider) (money.Money, error) {
+	currency = strings.ToUpper(currency)
+	for _, price := range prices {
+		if price.Price.Currency == currency {
+			return price.Price, nil
+		}
+	}
+	return money.Convert(money.IDR(base), currency, rates)
+}
+
+// ValidateProductPrice checks a price set for a currency other than the
+// base currency, which lives in Product.Price.
+func ValidateProductPrice(price money.Money) error {
+	if _, err := money.LookupCurrency(price.Currency); err != nil {
+		return err
+	}
+	if price.Currency == money.BaseCurrency {
+		return apperror.Validation("rupiah_price_not_allowed", "rupiah prices are set on the product itself")
+	}
+	if price.Amount <= 0 {
+		return apperror.Validation("invalid_price", "invalid price").WithField(validation.Field("amount", "gt", map[string]any{"value": 0}))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceIn(t *testing.T) {
+	rates := money.StaticRates{Base: "IDR", Rates: map[string]float64{"USD": 0.0000625, "SGD": 0.0000850}}
+	prices := []entity.ProductPrice{{ProductID: 1, Price: money.New(999, "USD")}}
+
+	price, err := PriceIn(160000, prices, "usd", rates)
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(999, "USD"), price)
+
+	price, err = PriceIn(160000, prices, "SGD", rates)
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(1360, "SGD"), price)
+
+	price, err = PriceIn(160000, prices, "IDR", rates)
+	assert.NoError(t, err)
+	assert.Equal(t, money.IDR(160000), price)
+}
+
+func TestValidateProductPrice(t *testing.T) {
+	assert.NoError(t, ValidateProductPrice(money.New(999, "USD")))
+	assertAppError(t, ValidateProductPrice(money.IDR(15000)), apperror.ErrValidation, "rupiah_price_not_allowed")
+	assertAppError(t, ValidateProductPrice(money.New(0, "USD")), apperror.ErrValidation, "invalid_price")
+	assert.Error(t, ValidateProductPrice(money.New(100, "XYZ")))
+}
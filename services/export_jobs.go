@@ -0,0 +1,126 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"e-commerce/entity"
+	"e-commerce/money"
+	"e-commerce/notify"
+	"e-commerce/storage"
+	"fmt"
+	"path"
+	"time"
+)
+
+// SalesReportJob exports the sales summary, daily revenue, top products and
+// top categories of the job's period as CSV files, and mails them to the
+// job's recipients.
+type SalesReportJob struct {
+	Reports ReportService
+	Store   storage.BlobStore
+	Mailer  notify.Mailer
+}
+
+func (srj SalesReportJob) Run(ctx context.Context, job entity.ScheduledJob, now time.Time) (string, error) {
+	r := JobPeriod(job, now)
+	summary, err := srj.Reports.Summary(ctx, r)
+	if err != nil {
+		return "", err
+	}
+	revenue, err := srj.Reports.RevenueReport(ctx, r, entity.ReportDaily)
+	if err != nil {
+		return "", err
+	}
+	products, err := srj.Reports.TopProducts(ctx, r, RankByRevenue, DefaultReportLimit)
+	if err != nil {
+		return "", err
+	}
+	categories, err := srj.Reports.TopCategories(ctx, r, RankByRevenue, DefaultReportLimit)
+	if err != nil {
+		return "", err
+	}
+
+	files := []notify.Attachment{
+		csvAttachment("summary.csv", SummaryRecords(*summary)),
+		csvAttachment("revenue.csv", RevenueRecords(revenue)),
+		csvAttachment("top-products.csv", RankedRecords(products)),
+		csvAttachment("top-categories.csv", RankedRecords(categories)),
+	}
+	body := fmt.Sprintf("Sales %s\n\nOrders: %d\nUnits sold: %d\nRevenue: %s\nAverage order value: %s\n",
+		periodLabel(r), summary.Orders, summary.Units,
+		money.New(summary.Revenue, money.BaseCurrency), money.New(summary.AverageOrderValue, money.BaseCurrency))
+	return exportFiles(srj.Store, srj.Mailer, job, r, "Sales report "+periodLabel(r), body, files)
+}
+
+// TransactionsExportJob exports every transaction of the job's period as a
+// CSV file, and mails it to the job's recipients.
+type TransactionsExportJob struct {
+	Transactions TransactionService
+	Store        storage.BlobStore
+	Mailer       notify.Mailer
+}
+
+func (tej TransactionsExportJob) Run(ctx context.Context, job entity.ScheduledJob, now time.Time) (string, error) {
+	r := JobPeriod(job, now)
+	// Transaction filters include To
+	to := r.To.Add(-time.Nanosecond)
+	filter := entity.TransactionFilter{From: &r.From, To: &to, Sort: "created_at", PageSize: MaxTransactionPageSize}
+
+	records := TransactionRecords(nil)
+	for filter.Page = 1; ; filter.Page++ {
+		page, err := tej.Transactions.SearchTransactions(ctx, filter)
+		if err != nil {
+			return "", err
+		}
+		for _, transaction := range page.Transactions {
+			records = append(records, TransactionRecord(transaction))
+		}
+		if int64(page.Page*page.PageSize) >= page.Total {
+			break
+		}
+	}
+
+	body := fmt.Sprintf("Transactions %s: %d\n", periodLabel(r), len(records)-1)
+	files := []notify.Attachment{csvAttachment("transactions.csv", records)}
+	return exportFiles(tej.Store, tej.Mailer, job, r, "Transactions "+periodLabel(r), body, files)
+}
+
+// JobPeriod is the PeriodDays whole UTC days before now, or just yesterday
+// when PeriodDays is not set.
+func JobPeriod(job entity.ScheduledJob, now time.Time) entity.ReportRange {
+	days := job.PeriodDays
+	if days < 1 {
+		days = 1
+	}
+	to := ReportPeriod(now, entity.ReportDaily)
+	return entity.ReportRange{From: to.AddDate(0, 0, -days), To: to}
+}
+
+func periodLabel(r entity.ReportRange) string {
+	last := r.To.AddDate(0, 0, -1)
+	if !last.After(r.From) {
+		return r.From.Format(time.DateOnly)
+	}
+	return r.From.Format(time.DateOnly) + " to " + last.Format(time.DateOnly)
+}
+
+func csvAttachment(filename string, records [][]string) notify.Attachment {
+	return notify.Attachment{Filename: filename, ContentType: "text/csv", Content: CSV(records)}
+}
+
+// exportFiles stores files under exports/<job name>/<first day of r> and
+// mails them when the job has recipients. It returns the storage prefix.
+func exportFiles(store storage.BlobStore, mailer notify.Mailer, job entity.ScheduledJob, r entity.ReportRange, subject, body string, files []notify.Attachment) (string, error) {
+	prefix := path.Join("exports", job.Name, r.From.Format(time.DateOnly))
+	for _, file := range files {
+		if err := store.Put(path.Join(prefix, file.Filename), bytes.NewReader(file.Content), file.ContentType); err != nil {
+			return "", err
+		}
+	}
+	if len(job.Recipients) > 0 {
+		if err := mailer.Send(notify.Mail{To: job.Recipients, Subject: subject, Body: body, Attachments: files}); err != nil {
+			return prefix, err
+		}
+	}
+	return prefix, nil
+}
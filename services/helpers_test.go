@@ -0,0 +1,25 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertAppError checks the kind and code of err instead of its message.
+func assertAppError(t *testing.T, err error, kind error, code string) {
+	t.Helper()
+	assert.ErrorIs(t, err, kind)
+	assert.Equal(t, code, apperror.Code(err))
+}
+
+// fieldOf returns the first field a validation error blames.
+func fieldOf(err error) string {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || len(appErr.Fields) == 0 {
+		return ""
+	}
+	return appErr.Fields[0].Field
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"fmt"
+)
+
+// ImageLimits bounds the dimensions of uploaded images, so a small file
+// can't decode into a huge bitmap. A zero limit is not checked.
+type ImageLimits struct {
+	MaxWidth  int
+	MaxHeight int
+	MaxPixels int
+}
+
+// Check reports whether an image of width by height pixels is allowed.
+func (il ImageLimits) Check(width, height int) error {
+	if (il.MaxWidth > 0 && width > il.MaxWidth) ||
+		(il.MaxHeight > 0 && height > il.MaxHeight) ||
+		(il.MaxPixels > 0 && width*height > il.MaxPixels) {
+		message := fmt.Sprintf("image must not be larger than %dx%d pixels or %d pixels in total", il.MaxWidth, il.MaxHeight, il.MaxPixels)
+		return apperror.New(apperror.ErrTooLarge, "image_dimensions_too_large", message).
+			With("max_width", il.MaxWidth).
+			With("max_height", il.MaxHeight).
+			With("max_pixels", il.MaxPixels)
+	}
+	return nil
+}
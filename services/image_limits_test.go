@@ -0,0 +1,19 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageLimitsCheck(t *testing.T) {
+	limits := ImageLimits{MaxWidth: 4000, MaxHeight: 3000, MaxPixels: 10_000_000}
+
+	assert.NoError(t, limits.Check(4000, 2500))
+	assert.True(t, errors.Is(limits.Check(4001, 10), apperror.ErrTooLarge))
+	assert.True(t, errors.Is(limits.Check(10, 3001), apperror.ErrTooLarge))
+	assert.True(t, errors.Is(limits.Check(4000, 3000), apperror.ErrTooLarge))
+	assert.NoError(t, ImageLimits{}.Check(100_000, 100_000))
+}
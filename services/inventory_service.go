@@ -0,0 +1,39 @@
+package services
+
+import (
+	"e-commerce/apperror"
+)
+
+// Inventory movement types. Receipts and refunds add stock, sales and
+// damage remove it and adjustments may go either way.
+const (
+	MovementReceipt    = "receipt"
+	MovementSale       = "sale"
+	MovementRefund     = "refund"
+	MovementAdjustment = "adjustment"
+	MovementDamage     = "damage"
+)
+
+// SignedQuantity converts the quantity of a movement into the change it
+// makes to stock. Sales are only recorded by purchases, not manually.
+func SignedQuantity(movementType string, quantity int) (int, error) {
+	switch movementType {
+	case MovementReceipt, MovementRefund:
+		if quantity <= 0 {
+			return 0, apperror.Validation("invalid_quantity", "quantity must be greater than zero")
+		}
+		return quantity, nil
+	case MovementSale, MovementDamage:
+		if quantity <= 0 {
+			return 0, apperror.Validation("invalid_quantity", "quantity must be greater than zero")
+		}
+		return -quantity, nil
+	case MovementAdjustment:
+		if quantity == 0 {
+			return 0, apperror.Validation("invalid_quantity", "quantity cannot be zero")
+		}
+		return quantity, nil
+	default:
+		return 0, apperror.Validation("invalid_movement_type", "invalid movement type")
+	}
+}
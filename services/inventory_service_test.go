@@ -0,0 +1,28 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedQuantity(t *testing.T) {
+	quantity, err := SignedQuantity(MovementReceipt, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, quantity)
+
+	quantity, err = SignedQuantity(MovementDamage, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, -2, quantity)
+
+	quantity, err = SignedQuantity(MovementAdjustment, -3)
+	assert.NoError(t, err)
+	assert.Equal(t, -3, quantity)
+
+	_, err = SignedQuantity(MovementReceipt, -1)
+	assertAppError(t, err, apperror.ErrValidation, "invalid_quantity")
+
+	_, err = SignedQuantity("lost", 1)
+	assertAppError(t, err, apperror.ErrValidation, "invalid_movement_type")
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"e-commerce/apperror"
+	"e-commerce/cron"
+	"e-commerce/entity"
+	"e-commerce/repository"
+	"e-commerce/tracing"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// JobRunner runs one type of scheduled job and returns where its output was
+// stored.
+type JobRunner interface {
+	Run(ctx context.Context, job entity.ScheduledJob, now time.Time) (output string, err error)
+}
+
+// JobService runs due scheduled jobs. Instance identifies this process when
+// claiming jobs, and LockTTL is how long a claim holds if the process dies
+// mid-run.
+type JobService struct {
+	Repository repository.JobRepo
+	Runners    map[string]JobRunner
+	Instance   string
+	LockTTL    time.Duration
+	Clock      func() time.Time
+}
+
+const defaultJobLockTTL = 30 * time.Minute
+
+// Job names are used in export paths.
+var jobNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateJob checks the name, type and cron schedule of job.
+func (js JobService) ValidateJob(job entity.ScheduledJob) error {
+	if !jobNamePattern.MatchString(job.Name) {
+		return apperror.Validation("invalid_job_name", "name must be lower case letters, digits, dashes and underscores")
+	}
+	if _, ok := js.Runners[job.Type]; !ok {
+		return apperror.Validation("unknown_job_type", fmt.Sprintf("unknown job type %q", job.Type)).With("type", job.Type)
+	}
+	if job.PeriodDays < 0 {
+		return apperror.Validation("invalid_period_days", "period_days must not be negative")
+	}
+	return cron.Validate(job.Schedule)
+}
+
+// NextJobRun returns the first time after now that schedule matches, in UTC.
+func NextJobRun(schedule string, now time.Time) (time.Time, error) {
+	parsed, err := cron.Parse(schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed.Next(now.UTC()), nil
+}
+
+// RunDueJobs runs every due job this instance manages to claim and returns
+// how many it ran.
+func (js JobService) RunDueJobs(ctx context.Context) (ran int, err error) {
+	ctx, span := tracing.Start(ctx, "JobService.RunDueJobs")
+	defer func() { tracing.End(span, err) }()
+
+	now := js.now()
+	jobs, err := js.Repository.FindDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	lockTTL := js.LockTTL
+	if lockTTL <= 0 {
+		lockTTL = defaultJobLockTTL
+	}
+	for _, job := range jobs {
+		claimed, err := js.Repository.Claim(ctx, job.ID, js.Instance, now, now.Add(lockTTL))
+		if err != nil {
+			return ran, err
+		}
+		if !claimed {
+			continue
+		}
+
+		job.LastRunAt = &now
+		job.LastOutput, err = js.run(ctx, job, now)
+		job.LastStatus, job.LastError = entity.JobSucceeded, ""
+		if err != nil {
+			job.LastStatus, job.LastError = entity.JobFailed, err.Error()
+			slog.Error("scheduled job failed", "job", job.Name, "type", job.Type, "error", err)
+		}
+		if job.NextRunAt, err = NextJobRun(job.Schedule, now); err != nil || job.NextRunAt.IsZero() {
+			// Keep a broken schedule from running every tick
+			job.NextRunAt = now.Add(24 * time.Hour)
+		}
+		if err := js.Repository.Finish(ctx, &job); err != nil {
+			return ran, err
+		}
+		ran++
+	}
+	return ran, nil
+}
+
+func (js JobService) run(ctx context.Context, job entity.ScheduledJob, now time.Time) (output string, err error) {
+	ctx, span := tracing.Start(ctx, "job "+job.Name, attribute.String("job.type", job.Type))
+	defer func() { tracing.End(span, err) }()
+
+	runner, ok := js.Runners[job.Type]
+	if !ok {
+		return "", fmt.Errorf("unknown job type %q", job.Type)
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return runner.Run(ctx, job, now)
+}
+
+// StartScheduler runs due jobs every interval until stop is called.
+func (js JobService) StartScheduler(interval time.Duration) (stop func()) {
+	return runEvery(interval, func(ctx context.Context) {
+		if _, err := js.RunDueJobs(ctx); err != nil {
+			slog.Error("running scheduled jobs failed", "error", err)
+		}
+	})
+}
+
+func (js JobService) now() time.Time {
+	if js.Clock != nil {
+		return js.Clock()
+	}
+	return time.Now()
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/notify"
+	"e-commerce/repository"
+	"e-commerce/storage"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var jobNow = time.Date(2024, 3, 15, 1, 0, 0, 0, time.UTC)
+
+type jobRunnerFunc func(job entity.ScheduledJob, now time.Time) (string, error)
+
+func (f jobRunnerFunc) Run(ctx context.Context, job entity.ScheduledJob, now time.Time) (string, error) {
+	return f(job, now)
+}
+
+type mailerStub struct {
+	sent []notify.Mail
+}
+
+func (ms *mailerStub) Send(mail notify.Mail) error {
+	ms.sent = append(ms.sent, mail)
+	return nil
+}
+
+func TestJobServiceValidateJob(t *testing.T) {
+	jobService := JobService{Runners: map[string]JobRunner{entity.JobSalesReport: SalesReportJob{}}}
+
+	assert.NoError(t, jobService.ValidateJob(entity.ScheduledJob{Name: "daily-sales", Type: entity.JobSalesReport, Schedule: "0 1 * * *"}))
+	assertAppError(t, jobService.ValidateJob(entity.ScheduledJob{Name: "../sales", Type: entity.JobSalesReport, Schedule: "0 1 * * *"}), apperror.ErrValidation, "invalid_job_name")
+	assertAppError(t, jobService.ValidateJob(entity.ScheduledJob{Name: "sales", Type: "backup", Schedule: "0 1 * * *"}), apperror.ErrValidation, "unknown_job_type")
+	assert.Error(t, jobService.ValidateJob(entity.ScheduledJob{Name: "sales", Type: entity.JobSalesReport, Schedule: "daily"}))
+}
+
+func TestJobServiceRunDueJobs(t *testing.T) {
+	jobRepo := &repository.JobRepoMock{}
+	var ran []string
+	jobService := JobService{
+		Repository: jobRepo,
+		Runners: map[string]JobRunner{
+			entity.JobSalesReport: jobRunnerFunc(func(job entity.ScheduledJob, now time.Time) (string, error) {
+				ran = append(ran, job.Name)
+				if job.Name == "broken" {
+					return "", errors.New("store unavailable")
+				}
+				return "exports/" + job.Name, nil
+			}),
+		},
+		Instance: "web-1",
+		LockTTL:  time.Minute,
+		Clock:    func() time.Time { return jobNow },
+	}
+
+	jobRepo.On("FindDue", mock.Anything, jobNow).Return([]entity.ScheduledJob{
+		{ID: 1, Name: "daily", Type: entity.JobSalesReport, Schedule: "0 1 * * *"},
+		{ID: 2, Name: "taken", Type: entity.JobSalesReport, Schedule: "0 1 * * *"},
+		{ID: 3, Name: "broken", Type: entity.JobSalesReport, Schedule: "0 * * * *"},
+	}, nil)
+	jobRepo.On("Claim", mock.Anything, uint(1), "web-1", jobNow, jobNow.Add(time.Minute)).Return(true, nil)
+	jobRepo.On("Claim", mock.Anything, uint(2), "web-1", jobNow, jobNow.Add(time.Minute)).Return(false, nil)
+	jobRepo.On("Claim", mock.Anything, uint(3), "web-1", jobNow, jobNow.Add(time.Minute)).Return(true, nil)
+	jobRepo.On("Finish", mock.Anything, mock.MatchedBy(func(job *entity.ScheduledJob) bool {
+		return job.ID == 1 && job.LastStatus == entity.JobSucceeded && job.LastOutput == "exports/daily" &&
+			job.NextRunAt.Equal(jobNow.AddDate(0, 0, 1)) && job.LastRunAt.Equal(jobNow)
+	})).Return(nil)
+	jobRepo.On("Finish", mock.Anything, mock.MatchedBy(func(job *entity.ScheduledJob) bool {
+		return job.ID == 3 && job.LastStatus == entity.JobFailed && job.LastError == "store unavailable" &&
+			job.NextRunAt.Equal(jobNow.Add(time.Hour))
+	})).Return(nil)
+
+	count, err := jobService.RunDueJobs(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{"daily", "broken"}, ran)
+	jobRepo.AssertExpectations(t)
+}
+
+func TestJobPeriod(t *testing.T) {
+	assert.Equal(t, entity.ReportRange{
+		From: time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+	}, JobPeriod(entity.ScheduledJob{}, jobNow))
+	assert.Equal(t, time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), JobPeriod(entity.ScheduledJob{PeriodDays: 7}, jobNow).From)
+}
+
+func TestSalesReportJob(t *testing.T) {
+	reportRepo := &repository.ReportRepoMock{}
+	store, err := storage.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	mailer := &mailerStub{}
+	job := SalesReportJob{Reports: ReportService{ReportRepository: reportRepo}, Store: store, Mailer: mailer}
+
+	r := JobPeriod(entity.ScheduledJob{}, jobNow)
+	reportRepo.On("Summary", mock.Anything, r).Return(&entity.SalesSummary{Orders: 2, Units: 3, Revenue: 150000}, nil)
+	reportRepo.On("RevenueByPeriod", mock.Anything, r, entity.ReportDaily).Return(nil, nil)
+	reportRepo.On("TopProducts", mock.Anything, r, RankByRevenue, DefaultReportLimit).Return([]entity.RankedItem{{ID: 1, Name: "Shirt", Orders: 2, Units: 3, Revenue: 150000}}, nil)
+	reportRepo.On("TopCategories", mock.Anything, r, RankByRevenue, DefaultReportLimit).Return(nil, nil)
+
+	output, err := job.Run(context.Background(), entity.ScheduledJob{Name: "daily-sales", Recipients: []string{"finance@example.com"}}, jobNow)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "exports/daily-sales/2024-03-14", output)
+	summary, _, err := store.Get("exports/daily-sales/2024-03-14/summary.csv")
+	assert.NoError(t, err)
+	content, _ := io.ReadAll(summary)
+	summary.Close()
+	assert.Equal(t, "orders,units,revenue,discount,tax,shipping,average_order_value\n2,3,150000,0,0,0,75000\n", string(content))
+
+	assert.Len(t, mailer.sent, 1)
+	assert.Equal(t, "Sales report 2024-03-14", mailer.sent[0].Subject)
+	assert.Contains(t, mailer.sent[0].Body, "Revenue: Rp150.000")
+	assert.Len(t, mailer.sent[0].Attachments, 4)
+}
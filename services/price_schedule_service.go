@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/repository"
+	"e-commerce/validation"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PriceScheduleService struct {
+	Repository repository.PriceScheduleRepo
+	Clock      func() time.Time
+}
+
+// ValidateScheduledPriceChange checks a new scheduled change. It must start
+// in the future and, when it has an end, end after it starts.
+func ValidateScheduledPriceChange(change entity.ScheduledPriceChange, now time.Time) error {
+	if err := validation.Price(change.Price); err != nil {
+		return err
+	}
+	if !change.StartsAt.After(now) {
+		return apperror.Validation("invalid_price_change_start", "price change must start in the future")
+	}
+	if change.EndsAt != nil && !change.EndsAt.After(change.StartsAt) {
+		return apperror.Validation("invalid_price_change_end", "price change must end after it starts")
+	}
+	return nil
+}
+
+// PriceChangesOverlap reports whether two changes to the same product would
+// be in effect at the same time. A change without an end is permanent and
+// only takes up the instant it starts.
+func PriceChangesOverlap(a, b entity.ScheduledPriceChange) bool {
+	switch {
+	case a.EndsAt == nil && b.EndsAt == nil:
+		return a.StartsAt.Equal(b.StartsAt)
+	case a.EndsAt == nil:
+		return !a.StartsAt.Before(b.StartsAt) && a.StartsAt.Before(*b.EndsAt)
+	case b.EndsAt == nil:
+		return !b.StartsAt.Before(a.StartsAt) && b.StartsAt.Before(*a.EndsAt)
+	default:
+		return a.StartsAt.Before(*b.EndsAt) && b.StartsAt.Before(*a.EndsAt)
+	}
+}
+
+// ApplyDueChanges starts pending changes whose start has passed and reverts
+// active changes whose end has passed. It returns how many were applied.
+// A change that fails is logged and retried on the next run without holding
+// up the others, and a change whose product was deleted is cancelled.
+func (pss PriceScheduleService) ApplyDueChanges(ctx context.Context) (int, error) {
+	now := pss.now()
+	changes, err := pss.Repository.FindDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for i := range changes {
+		change := &changes[i]
+		status := change.Status
+		ok, err := pss.applyDueChange(ctx, change, now)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			slog.Warn("cancelling scheduled price change of a deleted product", "change_id", change.ID, "product_id", change.ProductID)
+			change.Status = entity.PriceChangeCancelled
+			err = pss.Repository.UpdateStatus(ctx, change)
+		}
+		if err != nil {
+			change.Status = status
+			slog.Error("applying scheduled price change failed", "change_id", change.ID, "error", err)
+			continue
+		}
+		if ok {
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+// applyDueChange starts or ends change and reports whether it changed the
+// product price.
+func (pss PriceScheduleService) applyDueChange(ctx context.Context, change *entity.ScheduledPriceChange, now time.Time) (bool, error) {
+	switch change.Status {
+	case entity.PriceChangePending:
+		if change.EndsAt != nil && !now.Before(*change.EndsAt) {
+			// The whole window was missed, so there is nothing to apply
+			change.Status = entity.PriceChangeCompleted
+			return false, pss.Repository.UpdateStatus(ctx, change)
+		}
+		change.Status = entity.PriceChangeCompleted
+		if change.EndsAt != nil {
+			change.Status = entity.PriceChangeActive
+		}
+		return pss.Repository.ApplyScheduledChange(ctx, change, change.Price, entity.PriceReasonScheduleStart, now)
+	case entity.PriceChangeActive:
+		change.Status = entity.PriceChangeCompleted
+		current, err := pss.Repository.FindProductPrice(ctx, change.ProductID)
+		if err != nil {
+			return false, err
+		}
+		if change.RevertPrice == nil || current != change.Price {
+			// The price was changed by hand during the sale, keep it
+			return false, pss.Repository.UpdateStatus(ctx, change)
+		}
+		return pss.Repository.ApplyScheduledChange(ctx, change, *change.RevertPrice, entity.PriceReasonScheduleEnd, now)
+	default:
+		return false, nil
+	}
+}
+
+// StartScheduler applies due price changes every interval until stop is
+// called.
+func (pss PriceScheduleService) StartScheduler(interval time.Duration) (stop func()) {
+	return runEvery(interval, func(ctx context.Context) {
+		if _, err := pss.ApplyDueChanges(ctx); err != nil {
+			slog.Error("applying scheduled price changes failed", "error", err)
+		}
+	})
+}
+
+func (pss PriceScheduleService) now() time.Time {
+	if pss.Clock != nil {
+		return pss.Clock()
+	}
+	return time.Now()
+}
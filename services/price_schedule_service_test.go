@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var priceNow = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func TestValidateScheduledPriceChange(t *testing.T) {
+	tomorrow := priceNow.Add(24 * time.Hour)
+	yesterday := priceNow.Add(-24 * time.Hour)
+
+	assert.NoError(t, ValidateScheduledPriceChange(entity.ScheduledPriceChange{Price: 9000, StartsAt: tomorrow}, priceNow))
+	assertAppError(t, ValidateScheduledPriceChange(entity.ScheduledPriceChange{Price: 9000, StartsAt: yesterday}, priceNow), apperror.ErrValidation, "invalid_price_change_start")
+	assertAppError(t, ValidateScheduledPriceChange(entity.ScheduledPriceChange{Price: 9000, StartsAt: tomorrow, EndsAt: &tomorrow}, priceNow), apperror.ErrValidation, "invalid_price_change_end")
+}
+
+func TestPriceChangesOverlap(t *testing.T) {
+	day := func(d int) time.Time { return priceNow.AddDate(0, 0, d) }
+	sale := func(start, end int) entity.ScheduledPriceChange {
+		endsAt := day(end)
+		return entity.ScheduledPriceChange{StartsAt: day(start), EndsAt: &endsAt}
+	}
+	permanent := func(start int) entity.ScheduledPriceChange {
+		return entity.ScheduledPriceChange{StartsAt: day(start)}
+	}
+
+	assert.True(t, PriceChangesOverlap(sale(1, 5), sale(4, 8)))
+	assert.False(t, PriceChangesOverlap(sale(1, 5), sale(5, 8)))
+	assert.True(t, PriceChangesOverlap(sale(1, 5), permanent(3)))
+	assert.False(t, PriceChangesOverlap(permanent(5), sale(1, 5)))
+	assert.True(t, PriceChangesOverlap(permanent(2), permanent(2)))
+	assert.False(t, PriceChangesOverlap(permanent(2), permanent(3)))
+}
+
+func TestPriceScheduleServiceApplyDueChanges(t *testing.T) {
+	priceRepo := &repository.PriceScheduleRepoMock{}
+
+	saleEnd := priceNow.Add(48 * time.Hour)
+	revertPrice := 12000
+	changes := []entity.ScheduledPriceChange{
+		{ID: 1, ProductID: 1, Price: 9000, StartsAt: priceNow.Add(-time.Hour), EndsAt: &saleEnd, Status: entity.PriceChangePending},
+		{ID: 2, ProductID: 2, Price: 5000, StartsAt: priceNow.Add(-48 * time.Hour), EndsAt: &priceNow, RevertPrice: &revertPrice, Status: entity.PriceChangeActive},
+	}
+	priceRepo.On("FindDue", mock.Anything, priceNow).Return(changes, nil)
+	priceRepo.On("FindProductPrice", mock.Anything, uint(2)).Return(5000, nil)
+	priceRepo.On("ApplyScheduledChange", mock.Anything, mock.MatchedBy(func(change *entity.ScheduledPriceChange) bool {
+		return change.ID == 1 && change.Status == entity.PriceChangeActive
+	}), 9000, entity.PriceReasonScheduleStart, priceNow).Return(true, nil)
+	priceRepo.On("ApplyScheduledChange", mock.Anything, mock.MatchedBy(func(change *entity.ScheduledPriceChange) bool {
+		return change.ID == 2 && change.Status == entity.PriceChangeCompleted
+	}), 12000, entity.PriceReasonScheduleEnd, priceNow).Return(true, nil)
+
+	priceService := PriceScheduleService{Repository: priceRepo, Clock: func() time.Time { return priceNow }}
+
+	applied, err := priceService.ApplyDueChanges(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	priceRepo.AssertExpectations(t)
+}
+
+func TestPriceScheduleServiceKeepsManualPrice(t *testing.T) {
+	priceRepo := &repository.PriceScheduleRepoMock{}
+
+	revertPrice := 12000
+	changes := []entity.ScheduledPriceChange{
+		{ID: 2, ProductID: 2, Price: 5000, StartsAt: priceNow.Add(-48 * time.Hour), EndsAt: &priceNow, RevertPrice: &revertPrice, Status: entity.PriceChangeActive},
+	}
+	priceRepo.On("FindDue", mock.Anything, priceNow).Return(changes, nil)
+	priceRepo.On("FindProductPrice", mock.Anything, uint(2)).Return(7000, nil)
+	priceRepo.On("UpdateStatus", mock.Anything, mock.AnythingOfType("*entity.ScheduledPriceChange")).Return(nil)
+
+	priceService := PriceScheduleService{Repository: priceRepo, Clock: func() time.Time { return priceNow }}
+
+	applied, err := priceService.ApplyDueChanges(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, applied)
+	priceRepo.AssertNotCalled(t, "ApplyScheduledChange", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPriceScheduleServiceSkipsClaimedChange(t *testing.T) {
+	priceRepo := &repository.PriceScheduleRepoMock{}
+
+	saleEnd := priceNow.Add(24 * time.Hour)
+	changes := []entity.ScheduledPriceChange{
+		{ID: 1, ProductID: 1, Price: 9000, StartsAt: priceNow.Add(-time.Hour), EndsAt: &saleEnd, Status: entity.PriceChangePending},
+	}
+	priceRepo.On("FindDue", mock.Anything, priceNow).Return(changes, nil)
+	priceRepo.On("ApplyScheduledChange", mock.Anything, mock.AnythingOfType("*entity.ScheduledPriceChange"), 9000, entity.PriceReasonScheduleStart, priceNow).Return(false, nil)
+
+	priceService := PriceScheduleService{Repository: priceRepo, Clock: func() time.Time { return priceNow }}
+
+	applied, err := priceService.ApplyDueChanges(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, applied)
+}
+
+func TestPriceScheduleServiceContinuesPastDeletedProduct(t *testing.T) {
+	priceRepo := &repository.PriceScheduleRepoMock{}
+
+	revertPrice := 12000
+	changes := []entity.ScheduledPriceChange{
+		{ID: 1, ProductID: 1, Price: 9000, StartsAt: priceNow.Add(-2 * time.Hour), Status: entity.PriceChangePending},
+		{ID: 2, ProductID: 2, Price: 5000, StartsAt: priceNow.Add(-48 * time.Hour), EndsAt: &priceNow, RevertPrice: &revertPrice, Status: entity.PriceChangeActive},
+		{ID: 3, ProductID: 3, Price: 7000, StartsAt: priceNow.Add(-time.Hour), Status: entity.PriceChangePending},
+		{ID: 4, ProductID: 4, Price: 8000, StartsAt: priceNow.Add(-time.Minute), Status: entity.PriceChangePending},
+	}
+	priceRepo.On("FindDue", mock.Anything, priceNow).Return(changes, nil)
+	priceRepo.On("ApplyScheduledChange", mock.Anything, mock.MatchedBy(func(change *entity.ScheduledPriceChange) bool {
+		return change.ID == 1
+	}), 9000, entity.PriceReasonScheduleStart, priceNow).Return(false, gorm.ErrRecordNotFound)
+	priceRepo.On("FindProductPrice", mock.Anything, uint(2)).Return(0, gorm.ErrRecordNotFound)
+	priceRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(change *entity.ScheduledPriceChange) bool {
+		return (change.ID == 1 || change.ID == 2) && change.Status == entity.PriceChangeCancelled
+	})).Return(nil)
+	priceRepo.On("ApplyScheduledChange", mock.Anything, mock.MatchedBy(func(change *entity.ScheduledPriceChange) bool {
+		return change.ID == 3
+	}), 7000, entity.PriceReasonScheduleStart, priceNow).Return(false, errors.New("connection reset"))
+	priceRepo.On("ApplyScheduledChange", mock.Anything, mock.MatchedBy(func(change *entity.ScheduledPriceChange) bool {
+		return change.ID == 4
+	}), 8000, entity.PriceReasonScheduleStart, priceNow).Return(true, nil)
+
+	priceService := PriceScheduleService{Repository: priceRepo, Clock: func() time.Time { return priceNow }}
+
+	applied, err := priceService.ApplyDueChanges(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, applied)
+	priceRepo.AssertExpectations(t)
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/validation"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ProductImportColumns is the CSV header used by product import and export.
+var ProductImportColumns = []string{"sku", "title", "price", "stock", "category", "reorder_threshold", "weight"}
+
+// ProductImportRow is one product of an import file. Category is the
+// category Type; missing categories are created by the import.
+type ProductImportRow struct {
+	Row              int    `json:"-"`
+	SKU              string `json:"sku"`
+	Title            string `json:"title"`
+	Price            int    `json:"price"`
+	Stock            int    `json:"stock"`
+	Category         string `json:"category"`
+	ReorderThreshold int    `json:"reorder_threshold"`
+	Weight           int    `json:"weight"`
+	parseErrors      []ImportError
+}
+
+type ImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ParseProductImport reads rows from a "csv" or "json" file. Values that
+// cannot be parsed are reported by Validate rather than failing the file.
+func ParseProductImport(format string, r io.Reader) ([]ProductImportRow, error) {
+	switch format {
+	case "csv":
+		return parseProductCSV(r)
+	case "json":
+		return parseProductJSON(r)
+	default:
+		return nil, apperror.Validation("invalid_import_format", "format must be csv or json")
+	}
+}
+
+func parseProductCSV(r io.Reader) ([]ProductImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, apperror.Validation("empty_import_file", "import file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"title", "price", "stock", "category"} {
+		if _, ok := columns[required]; !ok {
+			return nil, apperror.Validation("missing_import_column", fmt.Sprintf("missing %s column", required)).With("column", required)
+		}
+	}
+
+	var rows []ProductImportRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		value := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := ProductImportRow{
+			Row:      line,
+			SKU:      value("sku"),
+			Title:    value("title"),
+			Category: value("category"),
+		}
+		row.Price = row.parseInt("price", value("price"))
+		row.Stock = row.parseInt("stock", value("stock"))
+		row.ReorderThreshold = row.parseInt("reorder_threshold", value("reorder_threshold"))
+		row.Weight = row.parseInt("weight", value("weight"))
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseProductJSON(r io.Reader) ([]ProductImportRow, error) {
+	var rows []ProductImportRow
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Row = i + 1
+	}
+	return rows, nil
+}
+
+func (row *ProductImportRow) parseInt(field, value string) int {
+	if value == "" {
+		return 0
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		row.parseErrors = append(row.parseErrors, ImportError{Row: row.Row, Field: field, Message: "must be a whole number"})
+	}
+	return number
+}
+
+// Validate returns every problem with the row.
+func (row ProductImportRow) Validate() []ImportError {
+	errs := append([]ImportError(nil), row.parseErrors...)
+	invalid := func(field, message string) {
+		errs = append(errs, ImportError{Row: row.Row, Field: field, Message: message})
+	}
+
+	if row.Title == "" {
+		invalid("title", "cannot be empty")
+	}
+	if row.Price == 0 {
+		invalid("price", "can't be empty or zero")
+	} else if err := validation.Price(row.Price); err != nil {
+		invalid("price", err.Error())
+	}
+	if row.Stock < 0 {
+		invalid("stock", "can't be negative")
+	}
+	if row.Category == "" {
+		invalid("category", "cannot be empty")
+	}
+	if row.ReorderThreshold < 0 {
+		invalid("reorder_threshold", "can't be negative")
+	}
+	if row.Weight < 0 {
+		invalid("weight", "can't be negative")
+	}
+	return errs
+}
+
+// Record returns the row in ProductImportColumns order.
+func (row ProductImportRow) Record() []string {
+	return []string{
+		row.SKU,
+		row.Title,
+		strconv.Itoa(row.Price),
+		strconv.Itoa(row.Stock),
+		row.Category,
+		strconv.Itoa(row.ReorderThreshold),
+		strconv.Itoa(row.Weight),
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProductImportCSV(t *testing.T) {
+	file := `sku,title,price,stock,category,reorder_threshold
+AC-01,AC,5000000,3,Electronics,1
+,Remote,abc,2,Electronics,
+`
+	rows, err := ParseProductImport("csv", strings.NewReader(file))
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, ProductImportRow{Row: 2, SKU: "AC-01", Title: "AC", Price: 5000000, Stock: 3, Category: "Electronics", ReorderThreshold: 1}, rows[0])
+	assert.Empty(t, rows[0].Validate())
+
+	assert.Equal(t, []ImportError{
+		{Row: 3, Field: "price", Message: "must be a whole number"},
+		{Row: 3, Field: "price", Message: "can't be empty or zero"},
+	}, rows[1].Validate())
+}
+
+func TestParseProductImportCSVMissingColumn(t *testing.T) {
+	_, err := ParseProductImport("csv", strings.NewReader("title,price,stock\nAC,1,1\n"))
+
+	assertAppError(t, err, apperror.ErrValidation, "missing_import_column")
+}
+
+func TestParseProductImportJSON(t *testing.T) {
+	file := `[{"title":"AC","price":60000000,"stock":-1,"category":"Electronics"}]`
+
+	rows, err := ParseProductImport("json", strings.NewReader(file))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ImportError{
+		{Row: 1, Field: "price", Message: "invalid price: price must be between 1 and 50,000,000"},
+		{Row: 1, Field: "stock", Message: "can't be negative"},
+	}, rows[0].Validate())
+}
+
+func TestProductImportRowRecord(t *testing.T) {
+	row := ProductImportRow{SKU: "AC-01", Title: "AC", Price: 5000, Stock: 2, Category: "Electronics", Weight: 12000}
+
+	assert.Equal(t, []string{"AC-01", "AC", "5000", "2", "Electronics", "0", "12000"}, row.Record())
+}
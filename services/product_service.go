@@ -1,13 +1,16 @@
 package services
 
 import (
+	"context"
+	"e-commerce/apperror"
 	"e-commerce/entity"
 	"e-commerce/repository"
-	"errors"
+	"e-commerce/validation"
 )
 
 type ProductService struct {
-	ProductRepository repository.ProductRepo
+	ProductRepository  repository.ProductRepo
+	CategoryRepository repository.CategoryRepo
 }
 type ProductInput struct {
 	ID         string
@@ -17,29 +20,32 @@ type ProductInput struct {
 	CategoryID int
 }
 
-func (ps ProductService) GetAllProducts() ([]entity.Product, error) {
-	products := ps.ProductRepository.FindAllProduct()
+func (ps ProductService) GetAllProducts(ctx context.Context) ([]entity.Product, error) {
+	products := ps.ProductRepository.FindAllProduct(ctx)
 	if len(products) == 0 {
-		return nil, errors.New("products not found")
+		return nil, apperror.NotFound("products_not_found", "products not found")
 	}
 	return products, nil
 }
-func (ps ProductService) CreateProduct(product entity.Product) error {
+func (ps ProductService) CreateProduct(ctx context.Context, product entity.Product) error {
 	if product.Title == "" || product.Price <= 0 || product.Stock < 0 || product.CategoryID == 0 {
-		return errors.New("invalid product input")
+		return apperror.Validation("invalid_product", "invalid product input")
+	}
+	if err := ps.validateCategory(ctx, product.CategoryID); err != nil {
+		return err
 	}
 
-	return ps.ProductRepository.CreateProduct(product)
+	return ps.ProductRepository.CreateProduct(ctx, product)
 }
 
-func (ps ProductService) UpdateProduct(productID string, userInput ProductInput) (*entity.Product, error) {
-	existingProduct, err := ps.ProductRepository.FindProductByID(productID)
+func (ps ProductService) UpdateProduct(ctx context.Context, productID string, userInput ProductInput) (*entity.Product, error) {
+	existingProduct, err := ps.ProductRepository.FindProductByID(ctx, productID)
 	if err != nil {
 		return nil, err
 	}
 
 	if existingProduct == nil {
-		return nil, errors.New("product not found")
+		return nil, apperror.NotFound("product_not_found", "product not found")
 	}
 
 	existingProduct.Title = userInput.Title
@@ -50,24 +56,27 @@ func (ps ProductService) UpdateProduct(productID string, userInput ProductInput)
 	if err := ps.validateProduct(existingProduct); err != nil {
 		return nil, err
 	}
+	if err := ps.validateCategory(ctx, existingProduct.CategoryID); err != nil {
+		return nil, err
+	}
 
-	err = ps.ProductRepository.UpdateProduct(existingProduct)
+	err = ps.ProductRepository.UpdateProduct(ctx, existingProduct)
 	if err != nil {
 		return nil, err
 	}
 
 	return existingProduct, nil
 }
-func (ps ProductService) DeleteProduct(productID string) error {
-	existingProduct, err := ps.ProductRepository.FindProductByID(productID)
+func (ps ProductService) DeleteProduct(ctx context.Context, productID string) error {
+	existingProduct, err := ps.ProductRepository.FindProductByID(ctx, productID)
 	if err != nil {
 		return err
 	}
 
 	if existingProduct == nil {
-		return errors.New("product not found")
+		return apperror.NotFound("product_not_found", "product not found")
 	}
-	err = ps.ProductRepository.DeleteProduct(existingProduct)
+	err = ps.ProductRepository.DeleteProduct(ctx, existingProduct)
 	if err != nil {
 		return err
 	}
@@ -77,7 +86,21 @@ func (ps ProductService) DeleteProduct(productID string) error {
 
 func (ps ProductService) validateProduct(product *entity.Product) error {
 	if product.Title == "" || product.Price <= 0 || product.Stock < 0 || product.CategoryID == 0 {
-		return errors.New("invalid product input")
+		return apperror.Validation("invalid_product", "invalid product input")
+	}
+	return nil
+}
+
+// validateCategory rejects a category_id that names no category the same way
+// the product handlers do, as unprocessable rather than not found.
+func (ps ProductService) validateCategory(ctx context.Context, categoryID int) error {
+	category, err := ps.CategoryRepository.FindByID(ctx, uint(categoryID))
+	if err != nil {
+		return err
+	}
+	if category == nil {
+		return apperror.Unprocessable("unknown_category", "category_id does not refer to an existing category",
+			validation.Field("category_id", "exists", nil))
 	}
 	return nil
 }
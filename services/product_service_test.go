@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"e-commerce/apperror"
 	"e-commerce/entity"
 	"e-commerce/repository"
 	"testing"
@@ -10,8 +12,10 @@ import (
 )
 
 var productRepo = &repository.ProductRepoMock{Mock: mock.Mock{}}
+var productCategoryRepo = &repository.CategoryRepoMock{Mock: mock.Mock{}}
 var productService = ProductService{
-	ProductRepository: productRepo,
+	ProductRepository:  productRepo,
+	CategoryRepository: productCategoryRepo,
 }
 
 func TestProductFindAll(t *testing.T) {
@@ -23,11 +27,11 @@ func TestProductFindAll(t *testing.T) {
 		{ID: "2", Title: "Remote", Price: 30000, Stock: 2, CategoryID: 2},
 	}
 
-	productRepo.On("FindAllProduct").Return(dummyProducts)
+	productRepo.On("FindAllProduct", mock.Anything).Return(dummyProducts)
 
 	productService := ProductService{ProductRepository: productRepo}
 
-	resultProducts, err := productService.GetAllProducts()
+	resultProducts, err := productService.GetAllProducts(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, dummyProducts, resultProducts)
@@ -45,14 +49,39 @@ func TestProductCreate(t *testing.T) {
 		CategoryID: 3,
 	}
 
-	productRepo.On("CreateProduct", dummyProduct).Return(nil)
+	productCategoryRepo.On("FindByID", mock.Anything, uint(3)).Return(&entity.Category{ID: 3, Type: "Gadget"}, nil)
+	productRepo.On("CreateProduct", mock.Anything, dummyProduct).Return(nil)
 
-	err := productService.CreateProduct(dummyProduct)
+	err := productService.CreateProduct(context.Background(), dummyProduct)
 
 	assert.NoError(t, err)
 
 	productRepo.AssertExpectations(t)
-	productRepo.Mock.AssertCalled(t, "CreateProduct", dummyProduct)
+	productRepo.Mock.AssertCalled(t, "CreateProduct", mock.Anything, dummyProduct)
+}
+
+func TestProductCreateUnknownCategory(t *testing.T) {
+	productRepo := &repository.ProductRepoMock{}
+	categoryRepo := &repository.CategoryRepoMock{}
+
+	dummyProduct := entity.Product{
+		Title:      "Tablet",
+		Price:      2000,
+		Stock:      1,
+		CategoryID: 99,
+	}
+
+	categoryRepo.On("FindByID", mock.Anything, uint(99)).Return(nil, nil)
+
+	productService := ProductService{ProductRepository: productRepo, CategoryRepository: categoryRepo}
+
+	err := productService.CreateProduct(context.Background(), dummyProduct)
+
+	assertAppError(t, err, apperror.ErrUnprocessable, "unknown_category")
+	assert.Equal(t, "category_id", fieldOf(err))
+
+	categoryRepo.AssertExpectations(t)
+	productRepo.AssertNotCalled(t, "CreateProduct", mock.Anything, dummyProduct)
 }
 
 func TestProductUpdate(t *testing.T) {
@@ -67,11 +96,14 @@ func TestProductUpdate(t *testing.T) {
 		CategoryID: 1,
 	}
 
-	productRepo.On("FindProductByID", "1").Return(dummyProduct, nil)
+	productRepo.On("FindProductByID", mock.Anything, "1").Return(dummyProduct, nil)
 
-	productRepo.On("UpdateProduct", dummyProduct).Return(nil)
+	productRepo.On("UpdateProduct", mock.Anything, dummyProduct).Return(nil)
 
-	productService := ProductService{ProductRepository: productRepo}
+	categoryRepo := &repository.CategoryRepoMock{}
+	categoryRepo.On("FindByID", mock.Anything, uint(2)).Return(&entity.Category{ID: 2, Type: "Electronics"}, nil)
+
+	productService := ProductService{ProductRepository: productRepo, CategoryRepository: categoryRepo}
 
 	updateInput := ProductInput{
 		ID:         "1",
@@ -81,7 +113,7 @@ func TestProductUpdate(t *testing.T) {
 		CategoryID: 2,
 	}
 
-	updatedProduct, err := productService.UpdateProduct(updateInput.ID, updateInput)
+	updatedProduct, err := productService.UpdateProduct(context.Background(), updateInput.ID, updateInput)
 
 	assert.NoError(t, err)
 
@@ -90,8 +122,8 @@ func TestProductUpdate(t *testing.T) {
 	assert.Equal(t, updateInput.Stock, updatedProduct.Stock)
 
 	productRepo.AssertExpectations(t)
-	productRepo.Mock.AssertCalled(t, "FindProductByID", "1")
-	productRepo.Mock.AssertCalled(t, "UpdateProduct", dummyProduct)
+	productRepo.Mock.AssertCalled(t, "FindProductByID", mock.Anything, "1")
+	productRepo.Mock.AssertCalled(t, "UpdateProduct", mock.Anything, dummyProduct)
 }
 func TestProductDelete(t *testing.T) {
 
@@ -105,17 +137,17 @@ func TestProductDelete(t *testing.T) {
 		CategoryID: 1,
 	}
 
-	productRepo.On("FindProductByID", "1").Return(dummyProduct, nil)
+	productRepo.On("FindProductByID", mock.Anything, "1").Return(dummyProduct, nil)
 
-	productRepo.On("DeleteProduct", dummyProduct).Return(nil)
+	productRepo.On("DeleteProduct", mock.Anything, dummyProduct).Return(nil)
 
 	productService := ProductService{ProductRepository: productRepo}
 
-	err := productService.DeleteProduct("1")
+	err := productService.DeleteProduct(context.Background(), "1")
 
 	assert.NoError(t, err)
 
 	productRepo.AssertExpectations(t)
-	productRepo.Mock.AssertCalled(t, "FindProductByID", "1")
-	productRepo.Mock.AssertCalled(t, "DeleteProduct", dummyProduct)
+	productRepo.Mock.AssertCalled(t, "FindProductByID", mock.Anything, "1")
+	productRepo.Mock.AssertCalled(t, "DeleteProduct", mock.Anything, dummyProduct)
 }
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"bytes"
+	"e-commerce/entity"
+	"encoding/csv"
+	"strconv"
+	"time"
+)
+
+// RevenueRecords returns the CSV rows of a revenue report, header first.
+func RevenueRecords(points []entity.RevenuePoint) [][]string {
+	records := [][]string{{"period", "orders", "units", "revenue"}}
+	for _, point := range points {
+		records = append(records, []string{point.Period.Format(time.DateOnly), formatInt(point.Orders), formatInt(point.Units), formatInt(point.Revenue)})
+	}
+	return records
+}
+
+// RankedRecords returns the CSV rows of a top list, header first.
+func RankedRecords(items []entity.RankedItem) [][]string {
+	records := [][]string{{"id", "name", "orders", "units", "revenue"}}
+	for _, item := range items {
+		records = append(records, []string{strconv.FormatUint(uint64(item.ID), 10), item.Name, formatInt(item.Orders), formatInt(item.Units), formatInt(item.Revenue)})
+	}
+	return records
+}
+
+// SummaryRecords returns the CSV header and row of a sales summary.
+func SummaryRecords(summary entity.SalesSummary) [][]string {
+	return [][]string{
+		{"orders", "units", "revenue", "discount", "tax", "shipping", "average_order_value"},
+		{formatInt(summary.Orders), formatInt(summary.Units), formatInt(summary.Revenue), formatInt(summary.Discount),
+			formatInt(summary.Tax), formatInt(summary.Shipping), formatInt(summary.AverageOrderValue)},
+	}
+}
+
+// TransactionRecords returns the CSV rows of transactions, header first.
+func TransactionRecords(transactions []entity.TransactionHistory) [][]string {
+	records := [][]string{{"id", "created_at", "user_id", "product_id", "sku", "title", "quantity", "subtotal", "discount", "tax", "shipping_cost", "total_price"}}
+	for _, transaction := range transactions {
+		records = append(records, TransactionRecord(transaction))
+	}
+	return records
+}
+
+func TransactionRecord(transaction entity.TransactionHistory) []string {
+	return []string{
+		transaction.ID,
+		transaction.CreatedAt.UTC().Format(time.RFC3339),
+		strconv.FormatUint(uint64(transaction.UserID), 10),
+		strconv.FormatUint(uint64(transaction.ProductID), 10),
+		transaction.Product.SKU,
+		transaction.Product.Title,
+		strconv.Itoa(transaction.Quantity),
+		strconv.Itoa(transaction.Subtotal),
+		strconv.Itoa(transaction.Discount),
+		strconv.Itoa(transaction.Tax),
+		strconv.Itoa(transaction.ShippingCost),
+		strconv.Itoa(transaction.TotalPrice),
+	}
+}
+
+// CSV encodes records as a CSV file.
+func CSV(records [][]string) []byte {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.WriteAll(records)
+	return buf.Bytes()
+}
+
+func formatInt(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
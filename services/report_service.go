@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/repository"
+	"e-commerce/tracing"
+	"e-commerce/validation"
+	"time"
+)
+
+type ReportService struct {
+	ReportRepository repository.ReportRepo
+	Clock            func() time.Time
+}
+
+// Reports cover DefaultReportDays days up to now unless asked otherwise. Top
+// lists hold DefaultReportLimit items, and never more than MaxReportLimit.
+const (
+	DefaultReportDays  = 30
+	DefaultReportLimit = 10
+	MaxReportLimit     = 100
+)
+
+// Top lists are ranked by revenue or by units sold.
+const (
+	RankByRevenue = "revenue"
+	RankByUnits   = "units"
+)
+
+var ErrInvalidReport = apperror.Validation("invalid_report", "invalid report")
+
+// ReportRange returns the range between from and to, defaulting to the last
+// DefaultReportDays days.
+func (rs ReportService) ReportRange(from, to *time.Time) (entity.ReportRange, error) {
+	r := entity.ReportRange{To: rs.now()}
+	if to != nil {
+		r.To = *to
+	}
+	r.From = r.To.AddDate(0, 0, -DefaultReportDays)
+	if from != nil {
+		r.From = *from
+	}
+	if !r.From.Before(r.To) {
+		return r, ErrInvalidReport.WithField(validation.Field("from", "before", map[string]any{"other": "to"}))
+	}
+	return r, nil
+}
+
+// RevenueReport returns the sales of every day, week or month in r, including
+// the periods without sales. Periods are in UTC and weeks start on Monday.
+func (rs ReportService) RevenueReport(ctx context.Context, r entity.ReportRange, granularity string) (points []entity.RevenuePoint, err error) {
+	ctx, span := tracing.Start(ctx, "ReportService.RevenueReport")
+	defer func() { tracing.End(span, err) }()
+
+	if granularity == "" {
+		granularity = entity.ReportDaily
+	}
+	if granularity != entity.ReportDaily && granularity != entity.ReportWeekly && granularity != entity.ReportMonthly {
+		return nil, ErrInvalidReport.WithField(validation.Field("granularity", "oneof", map[string]any{"values": "day, week, month"}))
+	}
+
+	found, err := rs.ReportRepository.RevenueByPeriod(ctx, r, granularity)
+	if err != nil {
+		return nil, err
+	}
+	byPeriod := make(map[time.Time]entity.RevenuePoint, len(found))
+	for _, point := range found {
+		byPeriod[point.Period.UTC()] = point
+	}
+
+	points = []entity.RevenuePoint{}
+	for period := ReportPeriod(r.From, granularity); period.Before(r.To); period = nextReportPeriod(period, granularity) {
+		point := byPeriod[period]
+		point.Period = period
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// ReportPeriod returns the start of the UTC day, week or month of t.
+func ReportPeriod(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	switch granularity {
+	case entity.ReportMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case entity.ReportWeekly:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func nextReportPeriod(period time.Time, granularity string) time.Time {
+	switch granularity {
+	case entity.ReportMonthly:
+		return period.AddDate(0, 1, 0)
+	case entity.ReportWeekly:
+		return period.AddDate(0, 0, 7)
+	default:
+		return period.AddDate(0, 0, 1)
+	}
+}
+
+func (rs ReportService) TopProducts(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error) {
+	return rs.top(ctx, "ReportService.TopProducts", rs.ReportRepository.TopProducts, r, by, limit)
+}
+
+func (rs ReportService) TopCategories(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error) {
+	return rs.top(ctx, "ReportService.TopCategories", rs.ReportRepository.TopCategories, r, by, limit)
+}
+
+func (rs ReportService) TopCustomers(ctx context.Context, r entity.ReportRange, by string, limit int) ([]entity.RankedItem, error) {
+	return rs.top(ctx, "ReportService.TopCustomers", rs.ReportRepository.TopCustomers, r, by, limit)
+}
+
+func (rs ReportService) top(ctx context.Context, name string, find func(context.Context, entity.ReportRange, string, int) ([]entity.RankedItem, error), r entity.ReportRange, by string, limit int) (items []entity.RankedItem, err error) {
+	ctx, span := tracing.Start(ctx, name)
+	defer func() { tracing.End(span, err) }()
+
+	if by == "" {
+		by = RankByRevenue
+	}
+	if by != RankByRevenue && by != RankByUnits {
+		return nil, ErrInvalidReport.WithField(validation.Field("by", "oneof", map[string]any{"values": "revenue, units"}))
+	}
+	if limit < 1 {
+		limit = DefaultReportLimit
+	}
+	items, err = find(ctx, r, by, min(limit, MaxReportLimit))
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []entity.RankedItem{}
+	}
+	return items, nil
+}
+
+// Summary returns the order count, units sold, revenue and average order
+// value in r. The average is rounded to the nearest unit.
+func (rs ReportService) Summary(ctx context.Context, r entity.ReportRange) (summary *entity.SalesSummary, err error) {
+	ctx, span := tracing.Start(ctx, "ReportService.Summary")
+	defer func() { tracing.End(span, err) }()
+
+	summary, err = rs.ReportRepository.Summary(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if summary.Orders > 0 {
+		summary.AverageOrderValue = (2*summary.Revenue + summary.Orders) / (2 * summary.Orders)
+	}
+	return summary, nil
+}
+
+func (rs ReportService) now() time.Time {
+	if rs.Clock != nil {
+		return rs.Clock()
+	}
+	return time.Now()
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"e-commerce/entity"
+	"e-commerce/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var reportNow = time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+func TestReportServiceReportRange(t *testing.T) {
+	reportService := ReportService{Clock: func() time.Time { return reportNow }}
+
+	r, err := reportService.ReportRange(nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ReportRange{From: reportNow.AddDate(0, 0, -DefaultReportDays), To: reportNow}, r)
+
+	from := reportNow.AddDate(0, 0, 1)
+	_, err = reportService.ReportRange(&from, nil)
+	assert.ErrorIs(t, err, ErrInvalidReport)
+}
+
+func TestReportPeriod(t *testing.T) {
+	// 2024-03-15 is a Friday
+	assert.Equal(t, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), ReportPeriod(reportNow, entity.ReportDaily))
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), ReportPeriod(reportNow, entity.ReportWeekly))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), ReportPeriod(reportNow, entity.ReportMonthly))
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), ReportPeriod(time.Date(2024, 3, 17, 23, 0, 0, 0, time.UTC), entity.ReportWeekly))
+}
+
+func TestReportServiceRevenueReportFillsGaps(t *testing.T) {
+	reportRepo := &repository.ReportRepoMock{}
+	reportService := ReportService{ReportRepository: reportRepo}
+
+	r := entity.ReportRange{From: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)}
+	reportRepo.On("RevenueByPeriod", mock.Anything, r, entity.ReportDaily).Return([]entity.RevenuePoint{
+		{Period: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Orders: 2, Units: 3, Revenue: 45000},
+	}, nil)
+
+	points, err := reportService.RevenueReport(context.Background(), r, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.RevenuePoint{
+		{Period: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Period: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Orders: 2, Units: 3, Revenue: 45000},
+		{Period: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)},
+	}, points)
+	reportRepo.AssertExpectations(t)
+}
+
+func TestReportServiceRevenueReportInvalidGranularity(t *testing.T) {
+	reportRepo := &repository.ReportRepoMock{}
+	reportService := ReportService{ReportRepository: reportRepo}
+
+	_, err := reportService.RevenueReport(context.Background(), entity.ReportRange{From: reportNow, To: reportNow.Add(time.Hour)}, "hour")
+
+	assert.ErrorIs(t, err, ErrInvalidReport)
+	assert.Equal(t, "granularity", fieldOf(err))
+	reportRepo.AssertNotCalled(t, "RevenueByPeriod", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReportServiceTopProducts(t *testing.T) {
+	reportRepo := &repository.ReportRepoMock{}
+	reportService := ReportService{ReportRepository: reportRepo}
+
+	r := entity.ReportRange{From: reportNow.AddDate(0, 0, -7), To: reportNow}
+	reportRepo.On("TopProducts", mock.Anything, r, RankByRevenue, DefaultReportLimit).Return(nil, nil)
+	reportRepo.On("TopProducts", mock.Anything, r, RankByUnits, MaxReportLimit).Return([]entity.RankedItem{{ID: 1, Name: "Shirt", Units: 40}}, nil)
+
+	items, err := reportService.TopProducts(context.Background(), r, "", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+	assert.NotNil(t, items)
+
+	items, err = reportService.TopProducts(context.Background(), r, RankByUnits, 500)
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	_, err = reportService.TopCustomers(context.Background(), r, "orders", 0)
+	assert.ErrorIs(t, err, ErrInvalidReport)
+	reportRepo.AssertExpectations(t)
+}
+
+func TestReportServiceSummary(t *testing.T) {
+	reportRepo := &repository.ReportRepoMock{}
+	reportService := ReportService{ReportRepository: reportRepo}
+
+	r := entity.ReportRange{From: reportNow.AddDate(0, 0, -7), To: reportNow}
+	reportRepo.On("Summary", mock.Anything, r).Return(&entity.SalesSummary{Orders: 3, Units: 5, Revenue: 100000}, nil).Once()
+	reportRepo.On("Summary", mock.Anything, r).Return(&entity.SalesSummary{}, nil).Once()
+
+	summary, err := reportService.Summary(context.Background(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(33333), summary.AverageOrderValue)
+
+	summary, err = reportService.Summary(context.Background(), r)
+	assert.NoError(t, err)
+	assert.Zero(t, summary.AverageOrderValue)
+	reportRepo.AssertExpectations(t)
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"e-commerce/repository"
+	"log/slog"
+	"time"
+)
+
+// ReservationService expires stale reservations in the background. Stock
+// is reserved and released by the reservation handlers, which lock the
+// product or variant row while they check what is still available.
+type ReservationService struct {
+	ReservationRepository repository.ReservationRepo
+	Clock                 func() time.Time
+}
+
+// ExpireReservations marks every active reservation past its expiry as
+// expired and returns how many there were.
+func (rs ReservationService) ExpireReservations(ctx context.Context) (int64, error) {
+	return rs.ReservationRepository.ExpireBefore(ctx, rs.now())
+}
+
+// StartSweeper expires reservations every interval until stop is called.
+func (rs ReservationService) StartSweeper(interval time.Duration) (stop func()) {
+	return runEvery(interval, func(ctx context.Context) {
+		if _, err := rs.ExpireReservations(ctx); err != nil {
+			slog.Error("expiring reservations failed", "error", err)
+		}
+	})
+}
+
+func (rs ReservationService) now() time.Time {
+	if rs.Clock != nil {
+		return rs.Clock()
+	}
+	return time.Now()
+}
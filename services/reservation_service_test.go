@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"e-commerce/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var reservationNow = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func TestReservationServiceExpireReservations(t *testing.T) {
+	reservationRepo := &repository.ReservationRepoMock{}
+
+	reservationRepo.On("ExpireBefore", mock.Anything, reservationNow).Return(int64(3), nil)
+
+	reservationService := ReservationService{
+		ReservationRepository: reservationRepo,
+		Clock:                 func() time.Time { return reservationNow },
+	}
+
+	expired, err := reservationService.ExpireReservations(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), expired)
+	reservationRepo.AssertExpectations(t)
+}
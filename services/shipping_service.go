@@ -0,0 +1,94 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"e-commerce/i18n"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Shipment is what a shipping cost is calculated for. Weight is in grams
+// and Subtotal is the price of the goods.
+type Shipment struct {
+	Province   string
+	City       string
+	PostalCode string
+	Weight     int
+	Subtotal   int
+}
+
+type ShippingCalculator interface {
+	Calculate(shipment Shipment) (int, error)
+}
+
+// FlatRateShipping charges Rate for every shipment, or nothing when the
+// subtotal reaches FreeAbove. A zero FreeAbove disables free shipping.
+type FlatRateShipping struct {
+	Rate      int
+	FreeAbove int
+}
+
+func (frs FlatRateShipping) Calculate(shipment Shipment) (int, error) {
+	if frs.FreeAbove > 0 && shipment.Subtotal >= frs.FreeAbove {
+		return 0, nil
+	}
+	return frs.Rate, nil
+}
+
+// ZoneRate prices a zone per started kilogram: FirstKg for the first one and
+// NextKg for each one after it.
+type ZoneRate struct {
+	FirstKg int `json:"first_kg"`
+	NextKg  int `json:"next_kg"`
+}
+
+// WeightZoneShipping looks up the zone of the destination province and
+// charges by weight. Provinces missing from Provinces use DefaultZone.
+type WeightZoneShipping struct {
+	Provinces   map[string]string   `json:"provinces"`
+	Zones       map[string]ZoneRate `json:"zones"`
+	DefaultZone string              `json:"default_zone"`
+}
+
+func (wzs WeightZoneShipping) Calculate(shipment Shipment) (int, error) {
+	if shipment.Weight < 0 {
+		return 0, apperror.Validation("invalid_weight", "weight can't be negative")
+	}
+	zone := wzs.DefaultZone
+	for province, provinceZone := range wzs.Provinces {
+		if strings.EqualFold(province, strings.TrimSpace(shipment.Province)) {
+			zone = provinceZone
+			break
+		}
+	}
+	rate, ok := wzs.Zones[zone]
+	if !ok {
+		return 0, apperror.Validation("shipping_unavailable", fmt.Sprintf("no shipping to %s", shipment.Province)).With("province", shipment.Province)
+	}
+	kilograms := max((shipment.Weight+999)/1000, 1)
+	return rate.FirstKg + (kilograms-1)*rate.NextKg, nil
+}
+
+// ValidateAddress checks that an address has everything a courier needs.
+// Indonesian postal codes are five digits.
+func ValidateAddress(address entity.Address) error {
+	required := []struct{ field, value string }{
+		{"recipient name", address.RecipientName},
+		{"phone", address.Phone},
+		{"street", address.Street},
+		{"city", address.City},
+		{"province", address.Province},
+		{"postal code", address.PostalCode},
+	}
+	for _, r := range required {
+		if strings.TrimSpace(r.value) == "" {
+			return apperror.Validation("address_field_required", fmt.Sprintf("%s cannot be empty", r.field)).With("field", i18n.Term(r.field))
+		}
+	}
+	if len(address.PostalCode) != 5 || strings.IndexFunc(address.PostalCode, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0 {
+		return apperror.Validation("invalid_postal_code", "postal code must be 5 digits")
+	}
+	return nil
+}
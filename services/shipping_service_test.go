@@ -0,0 +1,63 @@
+package services
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatRateShipping(t *testing.T) {
+	flat := FlatRateShipping{Rate: 15000, FreeAbove: 500000}
+
+	cost, err := flat.Calculate(Shipment{Subtotal: 120000})
+	assert.NoError(t, err)
+	assert.Equal(t, 15000, cost)
+
+	cost, err = flat.Calculate(Shipment{Subtotal: 500000})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cost)
+}
+
+func TestWeightZoneShipping(t *testing.T) {
+	zones := WeightZoneShipping{
+		Provinces:   map[string]string{"DKI Jakarta": "jawa", "Jawa Barat": "jawa", "Papua": "timur"},
+		Zones:       map[string]ZoneRate{"jawa": {FirstKg: 10000, NextKg: 5000}, "luar_jawa": {FirstKg: 20000, NextKg: 12000}},
+		DefaultZone: "luar_jawa",
+	}
+
+	cost, err := zones.Calculate(Shipment{Province: "dki jakarta", Weight: 250})
+	assert.NoError(t, err)
+	assert.Equal(t, 10000, cost)
+
+	// 2.1 kg is charged as 3 kg
+	cost, err = zones.Calculate(Shipment{Province: "Jawa Barat", Weight: 2100})
+	assert.NoError(t, err)
+	assert.Equal(t, 20000, cost)
+
+	cost, err = zones.Calculate(Shipment{Province: "Bali", Weight: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, 20000, cost)
+
+	_, err = zones.Calculate(Shipment{Province: "Papua", Weight: 1000})
+	assertAppError(t, err, apperror.ErrValidation, "shipping_unavailable")
+}
+
+func TestValidateAddress(t *testing.T) {
+	address := entity.Address{
+		RecipientName: "Budi",
+		Phone:         "081234567890",
+		Street:        "Jl. Merdeka No. 1",
+		City:          "Bandung",
+		Province:      "Jawa Barat",
+		PostalCode:    "40111",
+	}
+	assert.NoError(t, ValidateAddress(address))
+
+	address.PostalCode = "4011A"
+	assertAppError(t, ValidateAddress(address), apperror.ErrValidation, "invalid_postal_code")
+
+	address.City = " "
+	assertAppError(t, ValidateAddress(address), apperror.ErrValidation, "address_field_required")
+}
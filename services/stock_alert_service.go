@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"e-commerce/entity"
+	"e-commerce/i18n"
+	"e-commerce/notify"
+	"e-commerce/repository"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+type StockAlertService struct {
+	Repository     repository.StockAlertRepo
+	Notifier       notify.Notifier
+	AdminRecipient string
+	Clock          func() time.Time
+}
+
+// StockEventsFor returns the events caused by stock going from previous to
+// current. A threshold of zero disables low-stock events.
+func StockEventsFor(previous, current, threshold int) []string {
+	var events []string
+	switch {
+	case current <= 0 && previous > 0:
+		events = append(events, entity.StockEventOutOfStock)
+	case threshold > 0 && current <= threshold && previous > threshold:
+		events = append(events, entity.StockEventLowStock)
+	}
+	if previous <= 0 && current > 0 {
+		events = append(events, entity.StockEventBackInStock)
+	}
+	return events
+}
+
+// DispatchPending delivers up to limit undelivered stock events. Low and
+// out of stock events go to the admin recipient, back in stock events to
+// every waiting subscriber. Each event is claimed first and skipped when
+// another dispatcher holds it. An event that can't be delivered is recorded
+// as a failed attempt and the next one is tried, so one bad event doesn't
+// hold up the rest.
+func (sas StockAlertService) DispatchPending(ctx context.Context, limit int) (int, error) {
+	events, err := sas.Repository.FindPendingEvents(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	for _, event := range events {
+		now := sas.now()
+		claimed, err := sas.Repository.ClaimEvent(ctx, event.ID, now, now.Add(-entity.StockEventClaimTimeout))
+		if err != nil {
+			return dispatched, err
+		}
+		if !claimed {
+			continue
+		}
+		title, err := sas.Repository.FindProductTitle(ctx, event.ProductID)
+		if err == nil {
+			err = sas.dispatch(ctx, event, title)
+		}
+		if err != nil {
+			slog.Warn("dispatching stock event failed", "event_id", event.ID, "attempt", event.Attempts+1, "error", err)
+			if err := sas.Repository.MarkEventFailed(ctx, event.ID, err.Error()); err != nil {
+				return dispatched, err
+			}
+			continue
+		}
+		if err := sas.Repository.MarkEventDispatched(ctx, event.ID, sas.now()); err != nil {
+			return dispatched, err
+		}
+		dispatched++
+	}
+	return dispatched, nil
+}
+
+func (sas StockAlertService) dispatch(ctx context.Context, event entity.StockEvent, title string) error {
+	data := map[string]interface{}{
+		"product_id": event.ProductID,
+		"variant_id": event.VariantID,
+		"stock":      event.Stock,
+	}
+	switch event.Type {
+	case entity.StockEventLowStock:
+		return sas.Notifier.Notify(notify.Notification{
+			Event:     event.Type,
+			Recipient: sas.AdminRecipient,
+			Subject:   i18n.T(ctx, "notification.low_stock.subject", i18n.Params{"title": title}),
+			Message:   i18n.T(ctx, "notification.low_stock.message", i18n.Params{"title": title, "stock": event.Stock, "threshold": event.Threshold}),
+			Data:      data,
+		})
+	case entity.StockEventOutOfStock:
+		return sas.Notifier.Notify(notify.Notification{
+			Event:     event.Type,
+			Recipient: sas.AdminRecipient,
+			Subject:   i18n.T(ctx, "notification.out_of_stock.subject", i18n.Params{"title": title}),
+			Message:   i18n.T(ctx, "notification.out_of_stock.message", i18n.Params{"title": title}),
+			Data:      data,
+		})
+	case entity.StockEventBackInStock:
+		subscriptions, err := sas.Repository.FindWaitingSubscriptions(ctx, event.ProductID, event.VariantID)
+		if err != nil {
+			return err
+		}
+		// Notified subscriptions are marked one by one, so a retry only
+		// reaches the subscribers that failed
+		var failed []error
+		for _, subscription := range subscriptions {
+			if err := sas.Notifier.Notify(notify.Notification{
+				Event:     event.Type,
+				Recipient: subscription.Email,
+				Subject:   i18n.T(ctx, "notification.back_in_stock.subject", i18n.Params{"title": title}),
+				Message:   i18n.T(ctx, "notification.back_in_stock.message", i18n.Params{"name": subscription.FullName, "title": title}),
+				Data:      data,
+			}); err != nil {
+				failed = append(failed, err)
+				continue
+			}
+			if err := sas.Repository.MarkSubscriptionNotified(ctx, subscription.ID, sas.now()); err != nil {
+				return err
+			}
+		}
+		return errors.Join(failed...)
+	}
+	return nil
+}
+
+// StartDispatcher delivers pending stock events every interval until stop
+// is called.
+func (sas StockAlertService) StartDispatcher(interval time.Duration) (stop func()) {
+	return runEvery(interval, func(ctx context.Context) {
+		if _, err := sas.DispatchPending(ctx, 100); err != nil {
+			slog.Error("dispatching stock events failed", "error", err)
+		}
+	})
+}
+
+func (sas StockAlertService) now() time.Time {
+	if sas.Clock != nil {
+		return sas.Clock()
+	}
+	return time.Now()
+}
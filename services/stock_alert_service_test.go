@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"e-commerce/entity"
+	"e-commerce/i18n"
+	"e-commerce/notify"
+	"e-commerce/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type recordingNotifier struct {
+	notifications []notify.Notification
+}
+
+func (rn *recordingNotifier) Notify(notification notify.Notification) error {
+	rn.notifications = append(rn.notifications, notification)
+	return nil
+}
+
+func TestStockEventsFor(t *testing.T) {
+	assert.Equal(t, []string{entity.StockEventLowStock}, StockEventsFor(6, 5, 5))
+	assert.Nil(t, StockEventsFor(5, 4, 5))
+	assert.Nil(t, StockEventsFor(10, 4, 0))
+	assert.Equal(t, []string{entity.StockEventOutOfStock}, StockEventsFor(3, 0, 5))
+	assert.Equal(t, []string{entity.StockEventBackInStock}, StockEventsFor(0, 10, 5))
+}
+
+func TestStockAlertServiceDispatchPending(t *testing.T) {
+	alertRepo := &repository.StockAlertRepoMock{}
+	notifier := &recordingNotifier{}
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	alertRepo.On("FindPendingEvents", mock.Anything, 10).Return([]entity.StockEvent{
+		{ID: 1, ProductID: 7, Type: entity.StockEventLowStock, Stock: 2, Threshold: 3},
+		{ID: 2, ProductID: 7, Type: entity.StockEventBackInStock, Stock: 20},
+	}, nil)
+	alertRepo.On("ClaimEvent", mock.Anything, mock.AnythingOfType("uint"), now, now.Add(-entity.StockEventClaimTimeout)).Return(true, nil)
+	alertRepo.On("FindProductTitle", mock.Anything, uint(7)).Return("AC", nil)
+	alertRepo.On("FindWaitingSubscriptions", mock.Anything, uint(7), (*uint)(nil)).Return([]entity.RestockSubscription{
+		{ID: 4, UserID: 2, Email: "buyer@example.com", FullName: "Buyer", ProductID: 7},
+	}, nil)
+	alertRepo.On("MarkSubscriptionNotified", mock.Anything, uint(4), now).Return(nil)
+	alertRepo.On("MarkEventDispatched", mock.Anything, uint(1), now).Return(nil)
+	alertRepo.On("MarkEventDispatched", mock.Anything, uint(2), now).Return(nil)
+
+	alertService := StockAlertService{
+		Repository:     alertRepo,
+		Notifier:       notifier,
+		AdminRecipient: "admin@example.com",
+		Clock:          func() time.Time { return now },
+	}
+
+	dispatched, err := alertService.DispatchPending(context.Background(), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, dispatched)
+	assert.Len(t, notifier.notifications, 2)
+	assert.Equal(t, "admin@example.com", notifier.notifications[0].Recipient)
+	assert.Equal(t, "Low stock: AC", notifier.notifications[0].Subject)
+	assert.Equal(t, "buyer@example.com", notifier.notifications[1].Recipient)
+
+	alertRepo.AssertExpectations(t)
+}
+
+type failingNotifier struct {
+	recordingNotifier
+	failFor string
+}
+
+func (fn *failingNotifier) Notify(notification notify.Notification) error {
+	if notification.Recipient == fn.failFor {
+		return errors.New("mailbox unavailable")
+	}
+	return fn.recordingNotifier.Notify(notification)
+}
+
+func TestStockAlertServiceDispatchPendingContinuesAfterFailure(t *testing.T) {
+	alertRepo := &repository.StockAlertRepoMock{}
+	notifier := &failingNotifier{failFor: "gone@example.com"}
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	alertRepo.On("FindPendingEvents", mock.Anything, 10).Return([]entity.StockEvent{
+		{ID: 1, ProductID: 7, Type: entity.StockEventBackInStock, Stock: 20},
+		{ID: 2, ProductID: 8, Type: entity.StockEventOutOfStock},
+		{ID: 3, ProductID: 9, Type: entity.StockEventOutOfStock},
+	}, nil)
+	alertRepo.On("ClaimEvent", mock.Anything, mock.AnythingOfType("uint"), now, now.Add(-entity.StockEventClaimTimeout)).Return(true, nil)
+	alertRepo.On("FindProductTitle", mock.Anything, uint(7)).Return("AC", nil)
+	alertRepo.On("FindProductTitle", mock.Anything, uint(8)).Return("", errors.New("record not found"))
+	alertRepo.On("FindProductTitle", mock.Anything, uint(9)).Return("Fan", nil)
+	alertRepo.On("FindWaitingSubscriptions", mock.Anything, uint(7), (*uint)(nil)).Return([]entity.RestockSubscription{
+		{ID: 4, UserID: 2, Email: "gone@example.com", FullName: "Gone", ProductID: 7},
+		{ID: 5, UserID: 3, Email: "buyer@example.com", FullName: "Buyer", ProductID: 7},
+	}, nil)
+	alertRepo.On("MarkSubscriptionNotified", mock.Anything, uint(5), now).Return(nil)
+	alertRepo.On("MarkEventFailed", mock.Anything, uint(1), "mailbox unavailable").Return(nil)
+	alertRepo.On("MarkEventFailed", mock.Anything, uint(2), "record not found").Return(nil)
+	alertRepo.On("MarkEventDispatched", mock.Anything, uint(3), now).Return(nil)
+
+	alertService := StockAlertService{
+		Repository:     alertRepo,
+		Notifier:       notifier,
+		AdminRecipient: "admin@example.com",
+		Clock:          func() time.Time { return now },
+	}
+
+	dispatched, err := alertService.DispatchPending(context.Background(), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dispatched)
+	assert.Len(t, notifier.notifications, 2)
+	assert.Equal(t, "buyer@example.com", notifier.notifications[0].Recipient)
+	assert.Equal(t, "Out of stock: Fan", notifier.notifications[1].Subject)
+	alertRepo.AssertNotCalled(t, "MarkSubscriptionNotified", mock.Anything, uint(4), mock.Anything)
+	alertRepo.AssertExpectations(t)
+}
+
+func TestStockAlertServiceDispatchPendingSkipsClaimedEvents(t *testing.T) {
+	alertRepo := &repository.StockAlertRepoMock{}
+	notifier := &recordingNotifier{}
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	alertRepo.On("FindPendingEvents", mock.Anything, 10).Return([]entity.StockEvent{
+		{ID: 1, ProductID: 7, Type: entity.StockEventOutOfStock},
+		{ID: 2, ProductID: 9, Type: entity.StockEventOutOfStock},
+	}, nil)
+	alertRepo.On("ClaimEvent", mock.Anything, uint(1), now, now.Add(-entity.StockEventClaimTimeout)).Return(false, nil)
+	alertRepo.On("ClaimEvent", mock.Anything, uint(2), now, now.Add(-entity.StockEventClaimTimeout)).Return(true, nil)
+	alertRepo.On("FindProductTitle", mock.Anything, uint(9)).Return("Fan", nil)
+	alertRepo.On("MarkEventDispatched", mock.Anything, uint(2), now).Return(nil)
+
+	alertService := StockAlertService{
+		Repository:     alertRepo,
+		Notifier:       notifier,
+		AdminRecipient: "admin@example.com",
+		Clock:          func() time.Time { return now },
+	}
+
+	dispatched, err := alertService.DispatchPending(i18n.WithLocale(context.Background(), i18n.Indonesian), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dispatched)
+	assert.Len(t, notifier.notifications, 1)
+	assert.Equal(t, "Stok habis: Fan", notifier.notifications[0].Subject)
+	alertRepo.AssertNotCalled(t, "FindProductTitle", mock.Anything, uint(7))
+	alertRepo.AssertExpectations(t)
+}
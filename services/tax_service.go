@@ -0,0 +1,67 @@
+package services
+
+import "e-commerce/apperror"
+
+// TaxConfig describes how tax is charged. Rates are in hundredths of a
+// percent, so the 11% PPN is 1100. When PricesIncludeTax is set, product
+// prices already contain the tax and it is only broken out on the bill.
+type TaxConfig struct {
+	Name             string
+	DefaultRate      int
+	PricesIncludeTax bool
+}
+
+// TaxBreakdown is the tax charged on one purchase. Base is the amount the
+// tax was calculated on, excluding the tax itself.
+type TaxBreakdown struct {
+	Name     string `json:"name"`
+	Rate     int    `json:"rate"`
+	Included bool   `json:"included"`
+	Base     int    `json:"base"`
+	Tax      int    `json:"tax"`
+	Total    int    `json:"total"`
+}
+
+// MaxTaxRate is 100%.
+const MaxTaxRate = 10000
+
+func ValidateTaxRate(rate int) error {
+	if rate < 0 || rate > MaxTaxRate {
+		return apperror.Validation("invalid_tax_rate", "tax rate must be between 0 and 10000 (100%)")
+	}
+	return nil
+}
+
+// RateFor returns the category's own rate, or the default rate when the
+// category has none.
+func (tc TaxConfig) RateFor(categoryRate *int) int {
+	if categoryRate != nil {
+		return *categoryRate
+	}
+	return tc.DefaultRate
+}
+
+// Calculate returns the tax on amount, the price the customer pays after
+// discounts. Tax is rounded to the nearest rupiah.
+func (tc TaxConfig) Calculate(amount int, categoryRate *int) TaxBreakdown {
+	breakdown := TaxBreakdown{
+		Name:     tc.Name,
+		Rate:     tc.RateFor(categoryRate),
+		Included: tc.PricesIncludeTax,
+	}
+	if tc.PricesIncludeTax {
+		breakdown.Tax = divideRounded(amount*breakdown.Rate, MaxTaxRate+breakdown.Rate)
+		breakdown.Base = amount - breakdown.Tax
+		breakdown.Total = amount
+		return breakdown
+	}
+	breakdown.Base = amount
+	breakdown.Tax = divideRounded(amount*breakdown.Rate, MaxTaxRate)
+	breakdown.Total = amount + breakdown.Tax
+	return breakdown
+}
+
+// divideRounded divides non-negative integers, rounding half up.
+func divideRounded(numerator, denominator int) int {
+	return (2*numerator + denominator) / (2 * denominator)
+}
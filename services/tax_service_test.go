@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaxConfigCalculateExclusive(t *testing.T) {
+	ppn := TaxConfig{Name: "PPN", DefaultRate: 1100}
+
+	breakdown := ppn.Calculate(150000, nil)
+
+	assert.Equal(t, TaxBreakdown{Name: "PPN", Rate: 1100, Base: 150000, Tax: 16500, Total: 166500}, breakdown)
+}
+
+func TestTaxConfigCalculateInclusive(t *testing.T) {
+	ppn := TaxConfig{Name: "PPN", DefaultRate: 1100, PricesIncludeTax: true}
+
+	breakdown := ppn.Calculate(111000, nil)
+
+	assert.Equal(t, TaxBreakdown{Name: "PPN", Rate: 1100, Included: true, Base: 100000, Tax: 11000, Total: 111000}, breakdown)
+}
+
+func TestTaxConfigCalculateCategoryRate(t *testing.T) {
+	ppn := TaxConfig{Name: "PPN", DefaultRate: 1100}
+	exempt, reduced := 0, 550
+
+	assert.Equal(t, 0, ppn.Calculate(20000, &exempt).Tax)
+	assert.Equal(t, 20000, ppn.Calculate(20000, &exempt).Total)
+	// 999 * 5.5% = 54.945, rounded to 55
+	assert.Equal(t, 55, ppn.Calculate(999, &reduced).Tax)
+}
+
+func TestValidateTaxRate(t *testing.T) {
+	assert.NoError(t, ValidateTaxRate(1100))
+	assert.Error(t, ValidateTaxRate(-1))
+	assert.Error(t, ValidateTaxRate(10001))
+}
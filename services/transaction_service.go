@@ -1,9 +1,14 @@
 package services
 
 import (
+	"context"
+	"e-commerce/apperror"
 	"e-commerce/entity"
 	"e-commerce/repository"
-	"errors"
+	"e-commerce/tracing"
+	"e-commerce/validation"
+	"slices"
+	"strings"
 )
 
 type TransactionService struct {
@@ -17,9 +22,9 @@ type TransactionHistoryInput struct {
 	TotalPrice int
 }
 
-func (ts TransactionService) CreateTransactionHistory(input TransactionHistoryInput) error {
+func (ts TransactionService) CreateTransactionHistory(ctx context.Context, input TransactionHistoryInput) error {
 	if input.UserID == 0 || input.ProductID == 0 || input.Quantity <= 0 || input.TotalPrice <= 0 {
-		return errors.New("invalid transaction input")
+		return apperror.Validation("invalid_transaction", "invalid transaction input")
 	}
 
 	transaction := entity.TransactionHistory{
@@ -29,15 +34,86 @@ func (ts TransactionService) CreateTransactionHistory(input TransactionHistoryIn
 		TotalPrice: input.TotalPrice,
 	}
 
-	return ts.TransactionRepository.CreateTransactionHistory(transaction)
+	return ts.TransactionRepository.CreateTransactionHistory(ctx, transaction)
 }
-func (ts TransactionService) GetTransactionHistoryByUserID(userID uint) ([]entity.TransactionHistory, error) {
+func (ts TransactionService) GetTransactionHistoryByUserID(ctx context.Context, userID uint) ([]entity.TransactionHistory, error) {
 	if userID == 0 {
-		return nil, errors.New("invalid user ID")
+		return nil, apperror.Validation("invalid_user_id", "invalid user ID")
 	}
 
-	return ts.TransactionRepository.GetTransactionHistoryByUserID(userID)
+	return ts.TransactionRepository.GetTransactionHistoryByUserID(ctx, userID)
 }
-func (ts TransactionService) GetAllTransactionHistory() ([]entity.TransactionHistory, error) {
-	return ts.TransactionRepository.GetAllTransactionHistory()
+func (ts TransactionService) GetAllTransactionHistory(ctx context.Context) ([]entity.TransactionHistory, error) {
+	return ts.TransactionRepository.GetAllTransactionHistory(ctx)
+}
+
+// Transaction pages hold DefaultTransactionPageSize transactions unless
+// asked otherwise, and never more than MaxTransactionPageSize.
+const (
+	DefaultTransactionPageSize = 20
+	MaxTransactionPageSize     = 100
+)
+
+// TransactionSortColumns are the columns transactions can be sorted by.
+var TransactionSortColumns = []string{"created_at", "total_price", "quantity"}
+
+var (
+	ErrTransactionNotFound      = apperror.NotFound("transaction_not_found", "transaction not found")
+	ErrInvalidTransactionFilter = apperror.Validation("invalid_transaction_filter", "invalid transaction filter")
+)
+
+type TransactionPage struct {
+	Transactions []entity.TransactionHistory
+	Total        int64
+	Page         int
+	PageSize     int
+}
+
+// SearchTransactions returns one page of the transactions matching filter,
+// newest first unless another order is asked for.
+func (ts TransactionService) SearchTransactions(ctx context.Context, filter entity.TransactionFilter) (page *TransactionPage, err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.SearchTransactions")
+	defer func() { tracing.End(span, err) }()
+
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, ErrInvalidTransactionFilter.WithField(validation.Field("from", "before", map[string]any{"other": "to"}))
+	}
+	if filter.MinTotal != nil && filter.MaxTotal != nil && *filter.MinTotal > *filter.MaxTotal {
+		return nil, ErrInvalidTransactionFilter.WithField(validation.Field("min_total", "ltefield", map[string]any{"other": "max_total"}))
+	}
+	if filter.Sort == "" {
+		filter.Sort, filter.Descending = "created_at", true
+	}
+	if !slices.Contains(TransactionSortColumns, filter.Sort) {
+		return nil, ErrInvalidTransactionFilter.WithField(validation.Field("sort", "oneof", map[string]any{"values": strings.Join(TransactionSortColumns, ", ")}))
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = DefaultTransactionPageSize
+	}
+	filter.PageSize = min(filter.PageSize, MaxTransactionPageSize)
+
+	transactions, total, err := ts.TransactionRepository.FindTransactions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionPage{Transactions: transactions, Total: total, Page: filter.Page, PageSize: filter.PageSize}, nil
+}
+
+// GetTransaction returns a transaction of userID, or any transaction for
+// admins. Other users' transactions are reported as not found.
+func (ts TransactionService) GetTransaction(ctx context.Context, id, userID uint, admin bool) (*entity.TransactionHistory, error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.GetTransaction")
+	defer span.End()
+
+	transaction, err := ts.TransactionRepository.FindTransactionByID(ctx, id)
+	if err != nil {
+		return nil, ErrTransactionNotFound
+	}
+	if !admin && transaction.UserID != userID {
+		return nil, ErrTransactionNotFound
+	}
+	return transaction, nil
 }
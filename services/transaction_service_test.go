@@ -1,9 +1,11 @@
 package services
 
 import (
+	"context"
 	"e-commerce/entity"
 	"e-commerce/repository"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -25,11 +27,11 @@ func TestTransactionServiceCreateTransactionHistory(t *testing.T) {
 		TotalPrice: 300,
 	}
 
-	transactionRepo.On("CreateTransactionHistory", dummyTransaction).Return(nil)
+	transactionRepo.On("CreateTransactionHistory", mock.Anything, dummyTransaction).Return(nil)
 
 	transactionService := TransactionService{TransactionRepository: transactionRepo}
 
-	err := transactionService.CreateTransactionHistory(TransactionHistoryInput{
+	err := transactionService.CreateTransactionHistory(context.Background(), TransactionHistoryInput{
 		UserID:     dummyTransaction.UserID,
 		ProductID:  dummyTransaction.ProductID,
 		Quantity:   dummyTransaction.Quantity,
@@ -39,7 +41,7 @@ func TestTransactionServiceCreateTransactionHistory(t *testing.T) {
 	assert.NoError(t, err)
 
 	transactionRepo.AssertExpectations(t)
-	transactionRepo.Mock.AssertCalled(t, "CreateTransactionHistory", dummyTransaction)
+	transactionRepo.Mock.AssertCalled(t, "CreateTransactionHistory", mock.Anything, dummyTransaction)
 }
 func TestTransactionServiceGetTransactionHistoryByUserID(t *testing.T) {
 	transactionRepo := &repository.TransactionRepoMock{}
@@ -52,17 +54,17 @@ func TestTransactionServiceGetTransactionHistoryByUserID(t *testing.T) {
 		{UserID: userID, ProductID: 2, Quantity: 1, TotalPrice: 150},
 	}
 
-	transactionRepo.On("GetTransactionHistoryByUserID", userID).Return(dummyTransaction, nil)
+	transactionRepo.On("GetTransactionHistoryByUserID", mock.Anything, userID).Return(dummyTransaction, nil)
 
 	transactionService := TransactionService{TransactionRepository: transactionRepo}
 
-	resultTransactions, err := transactionService.GetTransactionHistoryByUserID(userID)
+	resultTransactions, err := transactionService.GetTransactionHistoryByUserID(context.Background(), userID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, dummyTransaction, resultTransactions)
 
 	transactionRepo.AssertExpectations(t)
-	transactionRepo.Mock.AssertCalled(t, "GetTransactionHistoryByUserID", userID)
+	transactionRepo.Mock.AssertCalled(t, "GetTransactionHistoryByUserID", mock.Anything, userID)
 }
 func TestTransactionServiceGetAllTransactionHistory(t *testing.T) {
 
@@ -73,15 +75,89 @@ func TestTransactionServiceGetAllTransactionHistory(t *testing.T) {
 		{UserID: 2, ProductID: 2, Quantity: 1, TotalPrice: 150},
 	}
 
-	transactionRepo.On("GetAllTransactionHistory").Return(dummyTransactions, nil)
+	transactionRepo.On("GetAllTransactionHistory", mock.Anything).Return(dummyTransactions, nil)
 
 	transactionService := TransactionService{TransactionRepository: transactionRepo}
 
-	resultTransactions, err := transactionService.GetAllTransactionHistory()
+	resultTransactions, err := transactionService.GetAllTransactionHistory(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, dummyTransactions, resultTransactions)
 
 	transactionRepo.AssertExpectations(t)
-	transactionRepo.Mock.AssertCalled(t, "GetAllTransactionHistory")
+	transactionRepo.Mock.AssertCalled(t, "GetAllTransactionHistory", mock.Anything)
+}
+func TestTransactionServiceSearchTransactionsDefaults(t *testing.T) {
+	transactionRepo := &repository.TransactionRepoMock{}
+
+	dummyTransactions := []entity.TransactionHistory{
+		{UserID: 1, ProductID: 1, Quantity: 2, TotalPrice: 200},
+	}
+	expectedFilter := entity.TransactionFilter{UserID: 1, Sort: "created_at", Descending: true, Page: 1, PageSize: DefaultTransactionPageSize}
+	transactionRepo.On("FindTransactions", mock.Anything, expectedFilter).Return(dummyTransactions, int64(41), nil)
+
+	transactionService := TransactionService{TransactionRepository: transactionRepo}
+
+	page, err := transactionService.SearchTransactions(context.Background(), entity.TransactionFilter{UserID: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &TransactionPage{Transactions: dummyTransactions, Total: 41, Page: 1, PageSize: DefaultTransactionPageSize}, page)
+	transactionRepo.AssertExpectations(t)
+}
+
+func TestTransactionServiceSearchTransactionsFilters(t *testing.T) {
+	transactionRepo := &repository.TransactionRepoMock{}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	minTotal := 10000
+	filter := entity.TransactionFilter{CategoryID: 3, From: &from, To: &to, MinTotal: &minTotal, Sort: "total_price", Page: 2, PageSize: 500}
+	expectedFilter := filter
+	expectedFilter.PageSize = MaxTransactionPageSize
+	transactionRepo.On("FindTransactions", mock.Anything, expectedFilter).Return([]entity.TransactionHistory{}, int64(0), nil)
+
+	transactionService := TransactionService{TransactionRepository: transactionRepo}
+
+	page, err := transactionService.SearchTransactions(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, MaxTransactionPageSize, page.PageSize)
+	transactionRepo.AssertExpectations(t)
+}
+
+func TestTransactionServiceSearchTransactionsInvalid(t *testing.T) {
+	transactionRepo := &repository.TransactionRepoMock{}
+	transactionService := TransactionService{TransactionRepository: transactionRepo}
+
+	from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+	_, err := transactionService.SearchTransactions(context.Background(), entity.TransactionFilter{From: &from, To: &to})
+	assert.ErrorIs(t, err, ErrInvalidTransactionFilter)
+	assert.Equal(t, "from", fieldOf(err))
+
+	_, err = transactionService.SearchTransactions(context.Background(), entity.TransactionFilter{Sort: "password"})
+	assert.ErrorIs(t, err, ErrInvalidTransactionFilter)
+	assert.Equal(t, "sort", fieldOf(err))
+
+	transactionRepo.AssertNotCalled(t, "FindTransactions", mock.Anything, mock.Anything)
+}
+
+func TestTransactionServiceGetTransaction(t *testing.T) {
+	transactionRepo := &repository.TransactionRepoMock{}
+
+	dummyTransaction := &entity.TransactionHistory{ID: "7", UserID: 1, ProductID: 2, Quantity: 1, TotalPrice: 150}
+	transactionRepo.On("FindTransactionByID", mock.Anything, uint(7)).Return(dummyTransaction, nil)
+
+	transactionService := TransactionService{TransactionRepository: transactionRepo}
+
+	transaction, err := transactionService.GetTransaction(context.Background(), 7, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, dummyTransaction, transaction)
+
+	_, err = transactionService.GetTransaction(context.Background(), 7, 2, false)
+	assert.ErrorIs(t, err, ErrTransactionNotFound)
+
+	transaction, err = transactionService.GetTransaction(context.Background(), 7, 2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, dummyTransaction, transaction)
 }
@@ -1,10 +1,12 @@
 package services
 
 import (
+	"context"
+	"e-commerce/apperror"
 	"e-commerce/entity"
 	"e-commerce/helpers"
 	"e-commerce/repository"
-	"errors"
+	"e-commerce/validation"
 )
 
 type UserService struct {
@@ -12,9 +14,9 @@ type UserService struct {
 }
 
 type RegisterInput struct {
-	FullName string
-	Email    string
-	Password string
+	FullName string `binding:"required"`
+	Email    string `binding:"required,email"`
+	Password string `binding:"required,password"`
 }
 type LoginInput struct {
 	Email    string
@@ -25,14 +27,10 @@ type UpdateUserBalanceInput struct {
 	Balance int
 }
 
-func (us *UserService) Register(input RegisterInput) (*entity.User, error) {
+func (us *UserService) Register(ctx context.Context, input RegisterInput) (*entity.User, error) {
 
-	if input.FullName == "" || input.Email == "" || input.Password == "" {
-		return nil, errors.New("full name, email, and password cannot be empty")
-	}
-
-	if len(input.Password) < 6 {
-		return nil, errors.New("password length must be at least 6 characters")
+	if err := validation.Struct(input); err != nil {
+		return nil, err
 	}
 
 	hashedPassword, err := helpers.HashPassword(input.Password)
@@ -48,48 +46,48 @@ func (us *UserService) Register(input RegisterInput) (*entity.User, error) {
 		Balance:  0,
 	}
 
-	err = us.UserRepository.Create(newUser)
+	err = us.UserRepository.Create(ctx, newUser)
 	if err != nil {
 		return nil, err
 	}
 
 	return newUser, nil
 }
-func (us *UserService) Login(input LoginInput) (*entity.User, error) {
+func (us *UserService) Login(ctx context.Context, input LoginInput) (*entity.User, error) {
 
-	user, err := us.UserRepository.FindByEmail(input.Email)
+	user, err := us.UserRepository.FindByEmail(ctx, input.Email)
 	if err != nil {
 		return nil, err
 	}
 	hashedPassword, err := helpers.HashPassword(user.Password)
 	if err != nil {
-		return nil, errors.New("failed to hash password")
+		return nil, apperror.Internal(err, "password_hash_failed", "failed to hash password")
 	}
 
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, apperror.NotFound("user_not_found", "user not found")
 	}
 
 	if err := helpers.ComparePassword(hashedPassword, input.Password); err != nil {
-		return nil, errors.New("incorrect password")
+		return nil, apperror.Unauthorized("incorrect_password", "incorrect password")
 	}
 
 	return user, nil
 }
-func (us *UserService) UpdateUserBalance(input UpdateUserBalanceInput) (*entity.User, error) {
+func (us *UserService) UpdateUserBalance(ctx context.Context, input UpdateUserBalanceInput) (*entity.User, error) {
 
-	user, err := us.UserRepository.FindByEmail(input.Email)
+	user, err := us.UserRepository.FindByEmail(ctx, input.Email)
 	if err != nil {
 		return nil, err
 	}
 
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, apperror.NotFound("user_not_found", "user not found")
 	}
 
 	user.Balance = input.Balance
 
-	err = us.UserRepository.UpdateBalance(user)
+	err = us.UserRepository.UpdateBalance(ctx, user)
 	if err != nil {
 		return nil, err
 	}
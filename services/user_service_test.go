@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"e-commerce/entity"
 	"e-commerce/repository"
 	"testing"
@@ -21,11 +22,11 @@ func TestUserService_Register(t *testing.T) {
 		Balance:  0,
 	}
 
-	userRepo.On("Create", mock.AnythingOfType("*entity.User")).Return(nil)
+	userRepo.On("Create", mock.Anything, mock.AnythingOfType("*entity.User")).Return(nil)
 
 	userService := &UserService{UserRepository: userRepo}
 
-	resultUser, err := userService.Register(RegisterInput{
+	resultUser, err := userService.Register(context.Background(), RegisterInput{
 		FullName: dummyUser.FullName,
 		Email:    dummyUser.Email,
 		Password: "dummy_password",
@@ -51,7 +52,7 @@ func TestUserService_Login(t *testing.T) {
 		Balance:  0,
 	}
 
-	userRepo.On("FindByEmail", dummyUser.Email).Return(dummyUser, nil)
+	userRepo.On("FindByEmail", mock.Anything, dummyUser.Email).Return(dummyUser, nil)
 
 	userService := &UserService{UserRepository: userRepo}
 
@@ -60,7 +61,7 @@ func TestUserService_Login(t *testing.T) {
 		Password: "felix123",
 	}
 
-	resultUser, err := userService.Login(loginInput)
+	resultUser, err := userService.Login(context.Background(), loginInput)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resultUser)
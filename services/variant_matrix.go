@@ -0,0 +1,58 @@
+package services
+
+import (
+	"e-commerce/entity"
+	"strings"
+)
+
+// VariantMatrixInput describes every size and colour combination of a product.
+// SKUs are generated from SKUPrefix, the size and the colour.
+type VariantMatrixInput struct {
+	SKUPrefix string
+	Sizes     []string
+	Colors    []string
+	Price     *int
+	Stock     int
+}
+
+// BuildVariantMatrix returns one variant per size and colour combination.
+// An empty list of sizes or colours is treated as a single blank option.
+func BuildVariantMatrix(productID uint, input VariantMatrixInput) []entity.ProductVariant {
+	sizes, colors := input.Sizes, input.Colors
+	if len(sizes) == 0 && len(colors) == 0 {
+		return nil
+	}
+	if len(sizes) == 0 {
+		sizes = []string{""}
+	}
+	if len(colors) == 0 {
+		colors = []string{""}
+	}
+
+	variants := make([]entity.ProductVariant, 0, len(sizes)*len(colors))
+	for _, size := range sizes {
+		for _, color := range colors {
+			variants = append(variants, entity.ProductVariant{
+				ProductID: productID,
+				SKU:       BuildSKU(input.SKUPrefix, size, color),
+				Size:      size,
+				Color:     color,
+				Price:     input.Price,
+				Stock:     input.Stock,
+			})
+		}
+	}
+	return variants
+}
+
+// BuildSKU joins the non-empty parts in upper case, e.g. "TSHIRT-XL-RED".
+func BuildSKU(parts ...string) string {
+	var segments []string
+	for _, part := range parts {
+		part = strings.ToUpper(strings.Join(strings.Fields(part), ""))
+		if part != "" {
+			segments = append(segments, part)
+		}
+	}
+	return strings.Join(segments, "-")
+}
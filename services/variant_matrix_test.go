@@ -0,0 +1,22 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildVariantMatrix(t *testing.T) {
+	variants := BuildVariantMatrix(1, VariantMatrixInput{
+		SKUPrefix: "T-Shirt",
+		Sizes:     []string{"S", "M", "L"},
+		Colors:    []string{"Red", "Navy Blue"},
+		Stock:     10,
+	})
+
+	assert.Len(t, variants, 6)
+	assert.Equal(t, "T-SHIRT-S-RED", variants[0].SKU)
+	assert.Equal(t, "T-SHIRT-L-NAVYBLUE", variants[5].SKU)
+	assert.Equal(t, 10, variants[5].Stock)
+	assert.Nil(t, BuildVariantMatrix(1, VariantMatrixInput{SKUPrefix: "T-Shirt"}))
+}
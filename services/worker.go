@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// runEvery calls tick every interval in its own goroutine until stop is
+// called. The context passed to tick is cancelled by stop, and stop returns
+// once a running tick has finished.
+func runEvery(interval time.Duration, tick func(ctx context.Context)) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := time.NewTicker(interval)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// select picks at random when stop and a tick are both ready
+				if ctx.Err() != nil {
+					return
+				}
+				tick(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-stopped
+	}
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunEvery(t *testing.T) {
+	var ticks atomic.Int32
+	var cancelled atomic.Bool
+	stop := runEvery(time.Millisecond, func(ctx context.Context) {
+		ticks.Add(1)
+		<-ctx.Done()
+		cancelled.Store(true)
+	})
+
+	assert.Eventually(t, func() bool { return ticks.Load() == 1 }, time.Second, time.Millisecond)
+	stop()
+
+	assert.True(t, cancelled.Load())
+	assert.Equal(t, int32(1), ticks.Load())
+}
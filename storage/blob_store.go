@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores binary objects such as product images under a key.
+type BlobStore interface {
+	Put(key string, body io.Reader, contentType string) error
+	Get(key string) (io.ReadCloser, string, error)
+	Delete(key string) error
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorePutGetDelete(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+
+	err = store.Put("products/1/image.png", strings.NewReader("png-bytes"), "image/png")
+	assert.NoError(t, err)
+
+	body, contentType, err := store.Get("products/1/image.png")
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "png-bytes", string(data))
+	assert.Equal(t, "image/png", contentType)
+
+	assert.NoError(t, store.Delete("products/1/image.png"))
+
+	_, _, err = store.Get("products/1/image.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalStoreRejectsTraversal(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+
+	err = store.Put("../outside.png", strings.NewReader("x"), "image/png")
+	assert.EqualError(t, err, "invalid blob key")
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// s3Stub is a minimal in-memory S3 endpoint.
+type s3Stub struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (stub *s3Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/20240102/us-east-1/s3/aws4_request") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		stub.objects[r.URL.Path] = data
+		stub.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, ok := stub.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", stub.types[r.URL.Path])
+		w.Write(data)
+	case http.MethodDelete:
+		delete(stub.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3StoreAgainstStub(t *testing.T) {
+	stub := &s3Stub{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	store := NewS3Store(server.URL, "images", "us-east-1", "access", "secret")
+	store.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	err := store.Put("products/1/photo one.jpg", strings.NewReader("jpeg-bytes"), "image/jpeg")
+	assert.NoError(t, err)
+	assert.Contains(t, stub.objects, "/images/products/1/photo one.jpg")
+
+	body, contentType, err := store.Get("products/1/photo one.jpg")
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "jpeg-bytes", string(data))
+	assert.Equal(t, "image/jpeg", contentType)
+
+	assert.NoError(t, store.Delete("products/1/photo one.jpg"))
+
+	_, _, err = store.Get("products/1/photo one.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
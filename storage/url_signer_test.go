@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLSigner(t *testing.T) {
+	signer := URLSigner{BaseURL: "http://localhost:8080/images", Secret: []byte("secret"), TTL: time.Minute}
+	now := time.Unix(1700000000, 0)
+
+	signedURL, err := url.Parse(signer.SignedURL("products/1/a.png", now))
+	assert.NoError(t, err)
+	assert.Equal(t, "/images/products/1/a.png", signedURL.Path)
+
+	expires := signedURL.Query().Get("expires")
+	signature := signedURL.Query().Get("signature")
+
+	assert.NoError(t, signer.Verify("products/1/a.png", expires, signature, now))
+	assert.ErrorIs(t, signer.Verify("products/2/a.png", expires, signature, now), ErrInvalidSignature)
+	assert.ErrorIs(t, signer.Verify("products/1/a.png", expires, signature, now.Add(2*time.Minute)), ErrInvalidSignature)
+}
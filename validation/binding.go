@@ -0,0 +1,35 @@
+package validation
+
+import "reflect"
+
+// Binding validates request bodies for gin. Install it with
+// binding.Validator = validation.Binding{} so ShouldBindJSON checks binding
+// tags with the rules of this package.
+type Binding struct{}
+
+func (b Binding) ValidateStruct(obj any) error {
+	if obj == nil {
+		return nil
+	}
+	value := reflect.ValueOf(obj)
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil
+		}
+		return b.ValidateStruct(value.Elem().Interface())
+	case reflect.Struct:
+		return Struct(obj)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := b.ValidateStruct(value.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (Binding) Engine() any {
+	return validate
+}
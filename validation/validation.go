@@ -0,0 +1,214 @@
+// Package validation checks request DTOs declared with binding tags and
+// holds the price, balance, email and password rules shared by handlers and
+// services.
+package validation
+
+import (
+	"e-commerce/apperror"
+	"e-commerce/i18n"
+	"errors"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	MaxPrice          = 50000000
+	MaxBalance        = 100000000
+	MinPasswordLength = 8
+	// bcrypt ignores everything after the 72nd byte
+	MaxPasswordLength = 72
+)
+
+// Custom rules, the code of the error their standalone check returns and
+// the parameters of their message.
+var rules = map[string]struct {
+	code   string
+	params map[string]any
+	valid  func(reflect.Value) bool
+}{
+	"price": {
+		code:   "invalid_price",
+		params: map[string]any{"min": 1, "max": MaxPrice},
+		valid:  func(v reflect.Value) bool { return validPrice(int(v.Int())) },
+	},
+	"balance": {
+		code:   "invalid_balance",
+		params: map[string]any{"min": 0, "max": MaxBalance},
+		valid:  func(v reflect.Value) bool { return validBalance(int(v.Int())) },
+	},
+	"email": {
+		code:  "invalid_email",
+		valid: func(v reflect.Value) bool { return validEmail(v.String()) },
+	},
+	"password": {
+		code:   "weak_password",
+		params: map[string]any{"min": MinPasswordLength, "max": MaxPasswordLength},
+		valid:  func(v reflect.Value) bool { return validPassword(v.String()) },
+	},
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	for tag, rule := range rules {
+		valid := rule.valid
+		if err := v.RegisterValidation(tag, func(fl validator.FieldLevel) bool { return valid(fl.Field()) }); err != nil {
+			panic(err)
+		}
+	}
+	return v
+}
+
+// Price checks a price in rupiah.
+func Price(price int) error {
+	return check("price", "price", validPrice(price))
+}
+
+// Balance checks a wallet balance in rupiah.
+func Balance(balance int) error {
+	return check("balance", "balance", validBalance(balance))
+}
+
+// Email checks that email is a bare RFC 5322 address, without a display
+// name.
+func Email(email string) error {
+	return check("email", "email", validEmail(email))
+}
+
+// Password checks the strength of a new password.
+func Password(password string) error {
+	return check("password", "password", validPassword(password))
+}
+
+func check(tag, field string, valid bool) error {
+	if valid {
+		return nil
+	}
+	rule := rules[tag]
+	message, _ := i18n.Translate(i18n.English, rule.code, nil)
+	return apperror.Validation(rule.code, message).WithField(Field(field, tag, rule.params))
+}
+
+// Field blames field for breaking rule, e.g. Field("from", "before",
+// map[string]any{"other": "to"}). Its message is rendered from the English
+// catalog entry "field.<rule>"; responses localize it again.
+func Field(field, rule string, params map[string]any) apperror.FieldError {
+	key := "field." + rule
+	if !i18n.Has(key) {
+		key = "field.invalid"
+	}
+	withField := map[string]any{"field": field}
+	for k, v := range params {
+		withField[k] = v
+	}
+	message, _ := i18n.Translate(i18n.English, key, withField)
+	return apperror.FieldError{Field: field, Code: rule, Message: message, Params: params}
+}
+
+func validPrice(price int) bool {
+	return price > 0 && price <= MaxPrice
+}
+
+func validBalance(balance int) bool {
+	return balance >= 0 && balance <= MaxBalance
+}
+
+func validEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
+
+func validPassword(password string) bool {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return false
+	}
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	classes := 0
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+	return classes >= 2
+}
+
+// Struct validates s by its binding tags. Every invalid field is listed in
+// the returned validation error.
+func Struct(s any) error {
+	err := validate.Struct(s)
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return err
+	}
+	message, _ := i18n.Translate(i18n.English, "invalid_fields", nil)
+	fieldsErr := apperror.Validation("invalid_fields", message)
+	for _, fieldErr := range invalid {
+		rule, params := ruleOf(fieldErr)
+		fieldsErr = fieldsErr.WithField(Field(fieldPath(fieldErr), rule, params))
+	}
+	return fieldsErr
+}
+
+// fieldPath drops the struct name from the namespace, leaving the JSON path
+// of the field, e.g. "variants[0].size".
+func fieldPath(fieldErr validator.FieldError) string {
+	_, path, found := strings.Cut(fieldErr.Namespace(), ".")
+	if !found {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+// ruleOf names the rule a field broke and the parameters of its message.
+// Length limits on strings are told apart from limits on numbers.
+func ruleOf(fieldErr validator.FieldError) (string, map[string]any) {
+	if rule, ok := rules[fieldErr.Tag()]; ok {
+		return fieldErr.Tag(), rule.params
+	}
+	var param any = fieldErr.Param()
+	if n, err := strconv.Atoi(fieldErr.Param()); err == nil {
+		param = n
+	}
+	suffix := ""
+	if fieldErr.Kind() == reflect.String {
+		suffix = "_length"
+	}
+	switch fieldErr.Tag() {
+	case "min", "gte":
+		return "min" + suffix, map[string]any{"min": param}
+	case "max", "lte":
+		return "max" + suffix, map[string]any{"max": param}
+	case "gt":
+		return "gt", map[string]any{"value": param}
+	case "oneof":
+		return "oneof", map[string]any{"values": strings.Join(strings.Fields(fieldErr.Param()), ", ")}
+	default:
+		return fieldErr.Tag(), nil
+	}
+}
@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"e-commerce/apperror"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrice(t *testing.T) {
+	assert.NoError(t, Price(1))
+	assert.NoError(t, Price(MaxPrice))
+	assert.Equal(t, "invalid_price", apperror.Code(Price(0)))
+	assert.Equal(t, "invalid_price", apperror.Code(Price(MaxPrice+1)))
+}
+
+func TestBalance(t *testing.T) {
+	assert.NoError(t, Balance(0))
+	assert.NoError(t, Balance(MaxBalance))
+	assert.Equal(t, "invalid_balance", apperror.Code(Balance(-1)))
+	assert.Equal(t, "invalid_balance", apperror.Code(Balance(MaxBalance+1)))
+}
+
+func TestEmail(t *testing.T) {
+	for _, email := range []string{"felix@example.com", "first.last+tag@sub.example.co.id"} {
+		assert.NoError(t, Email(email), email)
+	}
+	for _, email := range []string{"", "felix", "felix@", "@example.com", "a@b@c.com", "Felix <felix@example.com>", " felix@example.com"} {
+		assert.ErrorIs(t, Email(email), apperror.ErrValidation, email)
+	}
+}
+
+func TestPassword(t *testing.T) {
+	for _, password := range []string{"dummy_password", "hunter42", "PASSword"} {
+		assert.NoError(t, Password(password), password)
+	}
+	for _, password := range []string{"short1", "password", "12345678", string(make([]byte, MaxPasswordLength+1))} {
+		assert.Equal(t, "weak_password", apperror.Code(Password(password)), password)
+	}
+}
+
+type lineInput struct {
+	SKU   string `json:"sku" binding:"required"`
+	Price *int   `json:"price" binding:"omitempty,price"`
+}
+
+type orderInput struct {
+	Email  string      `json:"email" binding:"required,email"`
+	Status string      `json:"status" binding:"oneof=open closed"`
+	Stock  int         `json:"stock" binding:"gte=0"`
+	Lines  []lineInput `json:"lines" binding:"dive"`
+}
+
+func TestStructAggregatesFieldErrors(t *testing.T) {
+	zero := 0
+	err := Struct(orderInput{
+		Email:  "not-an-email",
+		Status: "pending",
+		Stock:  -1,
+		Lines:  []lineInput{{SKU: "TSHIRT"}, {Price: &zero}},
+	})
+
+	var appErr *apperror.Error
+	require.True(t, errors.As(err, &appErr))
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	assert.Equal(t, "invalid_fields", appErr.Code)
+	assert.Equal(t, []apperror.FieldError{
+		{Field: "email", Code: "email", Message: "email must be a valid email address"},
+		{Field: "status", Code: "oneof", Message: "status must be one of open, closed", Params: map[string]any{"values": "open, closed"}},
+		{Field: "stock", Code: "min", Message: "stock must be at least 0", Params: map[string]any{"min": 0}},
+		{Field: "lines[1].sku", Code: "required", Message: "lines[1].sku is required"},
+		{Field: "lines[1].price", Code: "price", Message: "lines[1].price must be between 1 and 50,000,000", Params: map[string]any{"min": 1, "max": MaxPrice}},
+	}, appErr.Fields)
+	assert.Equal(t, "invalid fields: email must be a valid email address; status must be one of open, closed; "+
+		"stock must be at least 0; lines[1].sku is required; lines[1].price must be between 1 and 50,000,000", err.Error())
+}
+
+func TestStructAcceptsValidInput(t *testing.T) {
+	price := 15000
+	assert.NoError(t, Struct(orderInput{
+		Email:  "felix@example.com",
+		Status: "open",
+		Lines:  []lineInput{{SKU: "TSHIRT", Price: &price}},
+	}))
+}
+
+func TestBindingValidatesPointersAndSlices(t *testing.T) {
+	var binding Binding
+	assert.NoError(t, binding.ValidateStruct(nil))
+	assert.NoError(t, binding.ValidateStruct((*lineInput)(nil)))
+	assert.ErrorIs(t, binding.ValidateStruct(&lineInput{}), apperror.ErrValidation)
+	assert.ErrorIs(t, binding.ValidateStruct([]lineInput{{SKU: "TSHIRT"}, {}}), apperror.ErrValidation)
+}
+
+type profileInput struct {
+	Name string `json:"name" binding:"min=3"`
+}
+
+func TestStructTellsLengthFromSize(t *testing.T) {
+	var appErr *apperror.Error
+	require.True(t, errors.As(Struct(profileInput{Name: "ab"}), &appErr))
+	assert.Equal(t, "min_length", appErr.Fields[0].Code)
+	assert.Equal(t, "name must be at least 3 characters", appErr.Fields[0].Message)
+}
+
+func TestField(t *testing.T) {
+	field := Field("from", "before", map[string]any{"other": "to"})
+	assert.Equal(t, apperror.FieldError{Field: "from", Code: "before", Message: "from must be before to", Params: map[string]any{"other": "to"}}, field)
+	assert.Equal(t, "sku is invalid", Field("sku", "alphanum", nil).Message)
+}